@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	configPath      string
+	configOverrides settingOverrides
+	configPrint     bool
+
+	tuningSettings []*TuningSetting
+	defaultTuning  map[string]json.RawMessage
+)
+
+// TuningSetting ties a name in the configuration file, such as
+// "explosion.whiteParticles", to the package-level variable it sets.
+type TuningSetting struct {
+	Name  string
+	Value interface{}
+	Min   float64
+	Max   float64
+}
+
+func unlimited(name string, value interface{}) *TuningSetting {
+	return &TuningSetting{Name: name, Value: value, Min: math.Inf(-1), Max: math.Inf(1)}
+}
+
+func limited(name string, value interface{}, min float64, max float64) *TuningSetting {
+	return &TuningSetting{Name: name, Value: value, Min: min, Max: max}
+}
+
+// The game settings are checked by simulation.Config.Validate, so only the
+// presentation settings carry their own limits.
+func initTuningSettings() {
+	tuningSettings = []*TuningSetting{
+		unlimited("game.startNumberOfAsteroids", &gameConfig.StartNumberOfAsteroids),
+		unlimited("game.startDelayBetweenAsteroids", &gameConfig.StartDelayBetweenAsteroids),
+		unlimited("game.startAsteroidVelocity", &gameConfig.StartAsteroidVelocity),
+		unlimited("game.startAsteroidY", &gameConfig.StartAsteroidY),
+		unlimited("game.asteroidsToSpawnIncrement", &gameConfig.AsteroidsToSpawnIncrement),
+		unlimited("game.delayBetweenAsteroidsIncrement", &gameConfig.DelayBetweenAsteroidsIncrement),
+		unlimited("game.asteroidVelocityIncrement", &gameConfig.AsteroidVelocityIncrement),
+		unlimited("game.minDelayBetweenAsteroids", &gameConfig.MinDelayBetweenAsteroids),
+		unlimited("game.asteroidMinDamage", &gameConfig.AsteroidMinDamage),
+		unlimited("game.asteroidMaxDamage", &gameConfig.AsteroidMaxDamage),
+		unlimited("game.asteroidSize", &gameConfig.AsteroidSize),
+		unlimited("game.asteroidSizePerLetter", &gameConfig.AsteroidSizePerLetter),
+		unlimited("game.asteroidMaxSize", &gameConfig.AsteroidMaxSize),
+		unlimited("game.asteroidSpawnMarginLeft", &gameConfig.AsteroidSpawnMarginLeft),
+		unlimited("game.asteroidSpawnMarginRight", &gameConfig.AsteroidSpawnMarginRight),
+		unlimited("game.diagonalFromLevel", &gameConfig.DiagonalFromLevel),
+		unlimited("game.wobbleFromLevel", &gameConfig.WobbleFromLevel),
+		unlimited("game.acceleratingFromLevel", &gameConfig.AcceleratingFromLevel),
+		unlimited("game.homingFromLevel", &gameConfig.HomingFromLevel),
+		unlimited("game.sideSpawnFromLevel", &gameConfig.SideSpawnFromLevel),
+		unlimited("game.sideSpawnChance", &gameConfig.SideSpawnChance),
+		unlimited("game.diagonalMaxAngle", &gameConfig.DiagonalMaxAngle),
+		unlimited("game.wobbleAmplitude", &gameConfig.WobbleAmplitude),
+		unlimited("game.wobblePeriod", &gameConfig.WobblePeriod),
+		unlimited("game.asteroidAcceleration", &gameConfig.AsteroidAcceleration),
+		unlimited("game.homingTurnRate", &gameConfig.HomingTurnRate),
+		unlimited("game.normalWeight", &gameConfig.NormalWeight),
+		unlimited("game.splitterWeight", &gameConfig.SplitterWeight),
+		unlimited("game.splitterFromLevel", &gameConfig.SplitterFromLevel),
+		unlimited("game.armoredWeight", &gameConfig.ArmoredWeight),
+		unlimited("game.armoredFromLevel", &gameConfig.ArmoredFromLevel),
+		unlimited("game.fastWeight", &gameConfig.FastWeight),
+		unlimited("game.fastFromLevel", &gameConfig.FastFromLevel),
+		unlimited("game.supplyWeight", &gameConfig.SupplyWeight),
+		unlimited("game.supplyFromLevel", &gameConfig.SupplyFromLevel),
+		unlimited("game.normalScore", &gameConfig.NormalScore),
+		unlimited("game.splitterScore", &gameConfig.SplitterScore),
+		unlimited("game.armoredScore", &gameConfig.ArmoredScore),
+		unlimited("game.fastScore", &gameConfig.FastScore),
+		unlimited("game.supplyScore", &gameConfig.SupplyScore),
+		unlimited("game.splitterFragments", &gameConfig.SplitterFragments),
+		unlimited("game.fragmentMaxWordLength", &gameConfig.FragmentMaxWordLength),
+		unlimited("game.armoredWords", &gameConfig.ArmoredWords),
+		unlimited("game.fastMaxWordLength", &gameConfig.FastMaxWordLength),
+		unlimited("game.fastVelocityMultiplier", &gameConfig.FastVelocityMultiplier),
+		unlimited("game.supplyHealth", &gameConfig.SupplyHealth),
+		unlimited("game.bossEveryLevels", &gameConfig.BossEveryLevels),
+		unlimited("game.bossSize", &gameConfig.BossSize),
+		unlimited("game.bossVelocityMultiplier", &gameConfig.BossVelocityMultiplier),
+		unlimited("game.bossScore", &gameConfig.BossScore),
+		unlimited("game.bossDamage", &gameConfig.BossDamage),
+		unlimited("game.bossShedDelay", &gameConfig.BossShedDelay),
+		unlimited("game.bossShedMaxWordLength", &gameConfig.BossShedMaxWordLength),
+		unlimited("game.playerStartHealth", &gameConfig.PlayerStartHealth),
+		unlimited("game.comboWordsPerMultiplier", &gameConfig.ComboWordsPerMultiplier),
+		unlimited("game.comboMaxMultiplier", &gameConfig.ComboMaxMultiplier),
+		unlimited("game.mistypeBreaksCombo", &gameConfig.MistypeBreaksCombo),
+		unlimited("game.mistypeLockout", &gameConfig.MistypeLockout),
+		unlimited("game.mistypeDamage", &gameConfig.MistypeDamage),
+
+		limited("explosion.whiteParticles", &explosionParticleEffectWhiteParticles, 0, 10000),
+		limited("explosion.yellowParticles", &explosionParticleEffectYellowParticles, 0, 10000),
+		limited("explosion.orangeParticles", &explosionParticleEffectOrangeParticles, 0, 10000),
+		limited("explosion.particleWidth", &explosionParticleEffectParticleWidth, 1, 64),
+		limited("explosion.particleHeight", &explosionParticleEffectParticleHeight, 1, 64),
+		limited("explosion.whiteAliveTime", &explosionParticleEffectWhiteParticleAliveTime, 0, 10000),
+		limited("explosion.whiteMinVelocity", &explosionParticleEffectWhiteParticleMinVelocity, -100, 100),
+		limited("explosion.whiteMaxVelocity", &explosionParticleEffectWhiteParticleMaxVelocity, -100, 100),
+		unlimited("explosion.whiteRed", &explosionParticleEffectWhiteParticleRed),
+		unlimited("explosion.whiteGreen", &explosionParticleEffectWhiteParticleGreen),
+		unlimited("explosion.whiteBlue", &explosionParticleEffectWhiteParticleBlue),
+		limited("explosion.yellowAliveTime", &explosionParticleEffectYellowParticleAliveTime, 0, 10000),
+		limited("explosion.yellowMinVelocity", &explosionParticleEffectYellowParticleMinVelocity, -100, 100),
+		limited("explosion.yellowMaxVelocity", &explosionParticleEffectYellowParticleMaxVelocity, -100, 100),
+		unlimited("explosion.yellowRed", &explosionParticleEffectYellowParticleRed),
+		unlimited("explosion.yellowGreen", &explosionParticleEffectYellowParticleGreen),
+		unlimited("explosion.yellowBlue", &explosionParticleEffectYellowParticleBlue),
+		limited("explosion.orangeAliveTime", &explosionParticleEffectOrangeParticleAliveTime, 0, 10000),
+		limited("explosion.orangeMinVelocity", &explosionParticleEffectOrangeParticleMinVelocity, -100, 100),
+		limited("explosion.orangeMaxVelocity", &explosionParticleEffectOrangeParticleMaxVelocity, -100, 100),
+		unlimited("explosion.orangeRed", &explosionParticleEffectOrangeParticleRed),
+		unlimited("explosion.orangeGreen", &explosionParticleEffectOrangeParticleGreen),
+		unlimited("explosion.orangeBlue", &explosionParticleEffectOrangeParticleBlue),
+
+		limited("jetBeam.particleWidth", &jetBeamParticleEffectParticleWidth, 1, 64),
+		limited("jetBeam.particleHeight", &jetBeamParticleEffectParticleHeight, 1, 64),
+		limited("jetBeam.particleVelocityX", &jetBeamParticleEffectParticleVelocityX, -10, 10),
+		limited("jetBeam.particleVelocityY", &jetBeamParticleEffectParticleVelocityY, -10, 10),
+		limited("jetBeam.yellowMinAliveTime", &jetBeamParticleEffectYellowParticleMinAliveTime, 1, 10000),
+		limited("jetBeam.yellowMaxAliveTime", &jetBeamParticleEffectYellowParticleMaxAliveTime, 1, 10000),
+		unlimited("jetBeam.yellowRed", &jetBeamParticleEffectYellowParticleRed),
+		unlimited("jetBeam.yellowGreen", &jetBeamParticleEffectYellowParticleGreen),
+		unlimited("jetBeam.yellowBlue", &jetBeamParticleEffectYellowParticleBlue),
+		limited("jetBeam.orangeMinAliveTime", &jetBeamParticleEffectOrangeParticleMinAliveTime, 1, 10000),
+		limited("jetBeam.orangeMaxAliveTime", &jetBeamParticleEffectOrangeParticleMaxAliveTime, 1, 10000),
+		unlimited("jetBeam.orangeRed", &jetBeamParticleEffectOrangeParticleRed),
+		unlimited("jetBeam.orangeGreen", &jetBeamParticleEffectOrangeParticleGreen),
+		unlimited("jetBeam.orangeBlue", &jetBeamParticleEffectOrangeParticleBlue),
+
+		limited("player.offsetY", &playerOffsetY, -4096, 0),
+		limited("player.jetBeamYellowParticles", &playerJetBeamYellowParticles, 0, 1000),
+		limited("player.jetBeamOrangeParticles", &playerJetBeamOrangeParticles, 0, 1000),
+
+		limited("asteroid.maxAngularVelocity", &asteroidMaxAngularVelocity, 0, 10),
+
+		unlimited("colors.asteroidWord", &asteroidRegularWordColor),
+		unlimited("colors.asteroidTargetedWord", &asteroidTargetedWordColor),
+		unlimited("colors.splitterAsteroid", &asteroidSplitterColor),
+		unlimited("colors.armoredAsteroid", &asteroidArmoredColor),
+		unlimited("colors.fastAsteroid", &asteroidFastColor),
+		unlimited("colors.supplyAsteroid", &asteroidSupplyColor),
+		unlimited("colors.bossAsteroid", &asteroidBossColor),
+		unlimited("colors.keyboardBackground", &keyboardBackgroundColor),
+		unlimited("colors.keyboardMistype", &keyboardMistypeColor),
+
+		limited("keyboard.keySize", &keyboardKeySize, 8, 200),
+		limited("keyboard.keyGap", &keyboardKeyGap, 0, 50),
+		limited("keyboard.margin", &keyboardMargin, 0, 1000),
+		limited("keyboard.flashTime", &keyboardFlashTime, 0, 10000),
+
+		limited("text.asteroidFontSize", &asteroidFontSize, 6, 200),
+		limited("text.currentWordFontSize", &currentWordFontSize, 6, 200),
+		limited("text.hudFontSize", &hudFontSize, 6, 200),
+		limited("text.levelFontSize", &levelFontSize, 6, 400),
+		limited("text.levelTimeToShow", &levelTimeToShow, 0, 60000),
+		limited("text.keyboardFontSize", &keyboardFontSize, 6, 200),
+	}
+}
+
+func findTuningSetting(name string) *TuningSetting {
+	for _, setting := range tuningSettings {
+		if setting.Name == name {
+			return setting
+		}
+	}
+	return nil
+}
+
+func (setting *TuningSetting) number() (float64, bool) {
+	switch value := setting.Value.(type) {
+	case *int:
+		return float64(*value), true
+	case *int32:
+		return float64(*value), true
+	case *float32:
+		return float64(*value), true
+	}
+	return 0, false
+}
+
+// Set decodes data as JSON into the variable of the setting, so numbers,
+// booleans and colors such as {"r": 255, "g": 160, "b": 40, "a": 255} are
+// written the same way on the command line and in the configuration file.
+func (setting *TuningSetting) Set(data []byte) error {
+	err := json.Unmarshal(data, setting.Value)
+	if err != nil {
+		return fmt.Errorf("setting %q: %v", setting.Name, err)
+	}
+	number, ok := setting.number()
+	if ok && (number < setting.Min || number > setting.Max) {
+		return fmt.Errorf("setting %q must be between %v and %v, got %v",
+			setting.Name, setting.Min, setting.Max, number)
+	}
+	return nil
+}
+
+func loadConfigFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return loadConfigData(path, data)
+}
+
+func loadConfigData(path string, data []byte) error {
+	sections := make(map[string]map[string]json.RawMessage)
+	err := json.Unmarshal(data, &sections)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	names := make([]string, 0)
+	for section, values := range sections {
+		for name := range values {
+			names = append(names, section+"."+name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		setting := findTuningSetting(name)
+		if setting == nil {
+			return fmt.Errorf("%s: unknown setting %q", path, name)
+		}
+		parts := strings.SplitN(name, ".", 2)
+		err = setting.Set(sections[parts[0]][parts[1]])
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+func validateConfiguration() error {
+	pairs := [][2]string{
+		{"explosion.whiteMinVelocity", "explosion.whiteMaxVelocity"},
+		{"explosion.yellowMinVelocity", "explosion.yellowMaxVelocity"},
+		{"explosion.orangeMinVelocity", "explosion.orangeMaxVelocity"},
+		{"jetBeam.yellowMinAliveTime", "jetBeam.yellowMaxAliveTime"},
+		{"jetBeam.orangeMinAliveTime", "jetBeam.orangeMaxAliveTime"},
+	}
+	for _, pair := range pairs {
+		min, _ := findTuningSetting(pair[0]).number()
+		max, _ := findTuningSetting(pair[1]).number()
+		if min >= max {
+			return fmt.Errorf("setting %q (%v) must be less than %q (%v)", pair[0], min, pair[1], max)
+		}
+	}
+	return gameConfig.Validate()
+}
+
+// loadConfiguration applies the configuration file and then the --set
+// overrides on top of the compiled-in defaults.
+func loadConfiguration() error {
+	if configPath != "" {
+		err := loadConfigFile(configPath)
+		if err != nil {
+			return err
+		}
+	}
+	for _, override := range configOverrides {
+		parts := strings.SplitN(override, "=", 2)
+		setting := findTuningSetting(parts[0])
+		if setting == nil {
+			return fmt.Errorf("--set: unknown setting %q", parts[0])
+		}
+		err := setting.Set([]byte(parts[1]))
+		if err != nil {
+			return fmt.Errorf("--set: %v", err)
+		}
+	}
+	return nil
+}
+
+// saveTuning returns the current value of every tuning setting, so that
+// they can be put back with restoreTuning.
+func saveTuning() map[string]json.RawMessage {
+	snapshot := make(map[string]json.RawMessage)
+	for _, setting := range tuningSettings {
+		data, err := json.Marshal(setting.Value)
+		if err != nil {
+			panic(err)
+		}
+		snapshot[setting.Name] = data
+	}
+	return snapshot
+}
+
+func restoreTuning(snapshot map[string]json.RawMessage) {
+	for _, setting := range tuningSettings {
+		err := json.Unmarshal(snapshot[setting.Name], setting.Value)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func printConfiguration() error {
+	sections := make(map[string]map[string]interface{})
+	for _, setting := range tuningSettings {
+		parts := strings.SplitN(setting.Name, ".", 2)
+		if sections[parts[0]] == nil {
+			sections[parts[0]] = make(map[string]interface{})
+		}
+		value := setting.Value
+		color, ok := value.(*sdl.Color)
+		if ok {
+			value = colorJSON(*color)
+		}
+		sections[parts[0]][parts[1]] = value
+	}
+	data, err := json.MarshalIndent(sections, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(os.Stdout, "%s\n", data)
+	return err
+}
+
+type settingOverrides []string
+
+func (overrides *settingOverrides) String() string {
+	return strings.Join(*overrides, ",")
+}
+
+func (overrides *settingOverrides) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("expected name=value, got %q", value)
+	}
+	*overrides = append(*overrides, value)
+	return nil
+}
+
+// sdl.Color has no JSON tags, so colors are printed with lower case keys
+// through this type. Decoding does not need it since JSON keys match field
+// names regardless of case.
+type colorJSON struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+	A uint8 `json:"a"`
+}
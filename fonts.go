@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+var (
+	// fallbackFontPaths are tried in order for text with characters that
+	// the game's font does not cover, such as Cyrillic or kana. Fonts given
+	// with --fallback-font are tried before these.
+	fallbackFontPaths []string = []string{
+		"/usr/share/fonts/truetype/dejavu/DejaVuSansMono.ttf",
+		"/usr/share/fonts/dejavu/DejaVuSansMono.ttf",
+		"/usr/share/fonts/TTF/DejaVuSansMono.ttf",
+		"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/google-noto-cjk/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+		"/System/Library/Fonts/Menlo.ttc",
+		"/System/Library/Fonts/Hiragino Sans GB.ttc",
+		"/Library/Fonts/Arial Unicode.ttf",
+		"C:\\Windows\\Fonts\\consola.ttf",
+		"C:\\Windows\\Fonts\\msgothic.ttc",
+		"C:\\Windows\\Fonts\\arialuni.ttf",
+	}
+	fallbackFonts fallbackFontsValue
+)
+
+// fallbackFontsValue collects the fonts given with --fallback-font, which
+// may be repeated or hold several paths separated like PATH.
+type fallbackFontsValue []string
+
+func (value *fallbackFontsValue) String() string {
+	return strings.Join(*value, string(os.PathListSeparator))
+}
+
+func (value *fallbackFontsValue) Set(s string) error {
+	*value = append(*value, filepath.SplitList(s)...)
+	return nil
+}
+
+// fontCovers reports whether the font has a glyph for every character of
+// the text. SDL_ttf draws characters a font lacks with its .notdef glyph,
+// so a character whose metrics are the same as those of the noncharacter
+// U+FFFF, which no font maps, counts as missing.
+func fontCovers(font *ttf.Font, text string) bool {
+	missing, err := font.GlyphMetrics(0xFFFF)
+	if err != nil {
+		return true
+	}
+	for _, character := range text {
+		if character > 0xFFFF {
+			return false
+		}
+		metrics, err := font.GlyphMetrics(character)
+		if err != nil || *metrics == *missing {
+			return false
+		}
+	}
+	return true
+}
+
+// FallbackFont returns the font when it covers the text, and otherwise the
+// first fallback font of the same size that does. The text is rendered
+// with one font, so a word is never drawn in a mix of typefaces.
+func (manager *ResourceManager) FallbackFont(font *ttf.Font, text string) *ttf.Font {
+	if fontCovers(font, text) {
+		return font
+	}
+	size, ok := manager.fontSizes[font]
+	if !ok {
+		return font
+	}
+	paths := append(append([]string{}, fallbackFonts...), fallbackFontPaths...)
+	for _, path := range paths {
+		fallback := manager.systemFont(path, size)
+		if fallback != nil && fontCovers(fallback, text) {
+			return fallback
+		}
+	}
+	return font
+}
+
+// systemFont opens a font outside the resources, or returns nil when
+// there is none at the path. Missing fonts are remembered so that the
+// same paths are not probed for every word.
+func (manager *ResourceManager) systemFont(path string, size int) *ttf.Font {
+	key := fontKey{path, size}
+	font, ok := manager.fonts[key]
+	if ok {
+		return font
+	}
+	if manager.missingFonts[path] {
+		return nil
+	}
+	font, err := ttf.OpenFont(path, size)
+	if err != nil {
+		manager.missingFonts[path] = true
+		return nil
+	}
+	manager.fonts[key] = font
+	manager.fontSizes[font] = size
+	return font
+}
+
+// renderText renders the text with the font, or a fallback font for the
+// characters it lacks.
+func renderText(font *ttf.Font, text string, color sdl.Color) (*sdl.Surface, error) {
+	return resources.FallbackFont(font, text).RenderUTF8Blended(text, color)
+}
@@ -1,199 +1,247 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"math/rand"
+	"path"
+	"strings"
 
-	"github.com/veandco/go-sdl2/img"
+	"github.com/snosscire/astrotyper/simulation"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 var (
-	startNumberOfAsteroids     int     = 5
-	startDelayBetweenAsteroids float32 = 2000.0
-	startAsteroidVelocity      float32 = 0.1
-	startAsteroidY             float32 = -64.0
-
-	asteroidsToSpawnIncrement      int     = 1
-	delayBetweenAsteroidsIncrement float32 = -100.0
-	asteroidVelocityIncrement      float32 = 0.01
-
 	asteroidRegularWordColor  sdl.Color = sdl.Color{R: 220, G: 50, B: 47, A: 255}
 	asteroidTargetedWordColor sdl.Color = sdl.Color{R: 133, G: 153, B: 0, A: 255}
 
-	minDelayBetweenAsteroids float32 = 1000.0
+	asteroidSplitterColor sdl.Color = sdl.Color{R: 255, G: 170, B: 90, A: 255}
+	asteroidArmoredColor  sdl.Color = sdl.Color{R: 140, G: 165, B: 210, A: 255}
+	asteroidFastColor     sdl.Color = sdl.Color{R: 255, G: 110, B: 110, A: 255}
+	asteroidSupplyColor   sdl.Color = sdl.Color{R: 120, G: 230, B: 120, A: 255}
+	asteroidBossColor     sdl.Color = sdl.Color{R: 200, G: 130, B: 255, A: 255}
 
-	asteroidMinDamage   int   = 5
-	asteroidMaxDamage   int   = 10
 	asteroidWordMargin  int32 = 10
 	asteroidWordPadding int32 = 1
 	asteroidWordBorder  int32 = 1
 
-	asteroid1TexturePath string = "resources/asteroid1.png"
-	asteroid2TexturePath string = "resources/asteroid2.png"
-	asteroid3TexturePath string = "resources/asteroid3.png"
-	asteroid4TexturePath string = "resources/asteroid4.png"
-	asteroidTextures     []*AsteroidTexture
+	bossBarWidth     int32  = 600
+	bossBarHeight    int32  = 16
+	bossBarMarginTop int32  = 24
+	bossSpaceLabel   string = "␣"
+	bossSentence     *Text
+	bossShown        *simulation.Asteroid
+
+	asteroidTextureDirectory   string  = "resources/asteroids"
+	asteroidMaxAngularVelocity float32 = 0.06
+	asteroidTextures           []*AsteroidTexture
 
-	wordList []string
+	gameConfig simulation.Config = simulation.DefaultConfig()
+
+	asteroidSprites map[*simulation.Asteroid]*AsteroidSprite
+	explosions      []*ExplosionParticleEffect
 )
 
+// AsteroidSheet describes an asteroid texture that is a sprite sheet. It is
+// read from a JSON file next to the image with the same name, such as
+// rock.json for rock.png. The frames are laid out in rows from the top left.
+type AsteroidSheet struct {
+	Columns   int     `json:"columns"`
+	Rows      int     `json:"rows"`
+	FrameTime float32 `json:"frameTime"`
+}
+
+// AsteroidTexture is an image of an asteroid, or a sprite sheet of one
+// spinning or glowing. Width and Height are the size of a single frame.
 type AsteroidTexture struct {
-	Texture *sdl.Texture
-	Width   int32
-	Height  int32
+	Texture   *sdl.Texture
+	Width     int32
+	Height    int32
+	Columns   int
+	Frames    int
+	FrameTime float32
 }
 
-type Asteroid struct {
+func (texture *AsteroidTexture) Frame(frame int) *sdl.Rect {
+	return &sdl.Rect{
+		X: int32(frame%texture.Columns) * texture.Width,
+		Y: int32(frame/texture.Columns) * texture.Height,
+		W: texture.Width,
+		H: texture.Height,
+	}
+}
+
+type AsteroidSprite struct {
 	rectangle         sdl.Rect
-	alive             bool
-	destroyed         bool
-	targeted          bool
-	x                 float32
-	y                 float32
-	velocity          float32
+	asteroid          *simulation.Asteroid
 	texture           *AsteroidTexture
+	angle             float64
+	angularVelocity   float64
+	frame             int
+	frameTimeLeft     float32
+	targeted          bool
 	word              string
 	wordTexture       *sdl.Texture
 	wordTextureWidth  int32
 	wordTextureHeight int32
-	explosion         *ExplosionParticleEffect
 }
 
-type AsteroidNotDestroyed func(int)
-type NextLevel func(int)
-
-type Game struct {
-	asteroids                  []*Asteroid
-	level                      int
-	numberOfAsteroidsToSpawn   int
-	asteroidsLeftToSpawn       int
-	delayBetweenAsteroids      float32
-	timeUntilNextAsteroidSpawn float32
-	asteroidVelocity           float32
-	asteroidNotDestroyed       AsteroidNotDestroyed
-	nextLevel                  NextLevel
+// NewAsteroidSprite gives the asteroid a random rotation and spin, and
+// scales its texture to the size of the asteroid so that longer words come
+// on bigger rocks. The rotation and spin are drawn from the game's seed and
+// the asteroid's ID, so a replay shows the same rocks.
+func NewAsteroidSprite(asteroid *simulation.Asteroid) *AsteroidSprite {
+	sprite := &AsteroidSprite{}
+	sprite.asteroid = asteroid
+	// Replays keep the number of variants they were recorded with, which
+	// may be more than the textures found now.
+	sprite.texture = asteroidTextures[asteroid.Variant()%len(asteroidTextures)]
+	random := rand.New(rand.NewSource(currentGame.Seed() + int64(asteroid.ID())))
+	sprite.angle = random.Float64() * 360.0
+	sprite.angularVelocity = (random.Float64()*2.0 - 1.0) * float64(asteroidMaxAngularVelocity)
+	sprite.frame = random.Intn(sprite.texture.Frames)
+	sprite.frameTimeLeft = sprite.texture.FrameTime
+	sprite.targeted = asteroid.IsTargeted()
+
+	scale := asteroid.Size() / float32(sprite.texture.Width)
+	if sprite.texture.Height > sprite.texture.Width {
+		scale = asteroid.Size() / float32(sprite.texture.Height)
+	}
+	sprite.rectangle.W = int32(float32(sprite.texture.Width) * scale)
+	sprite.rectangle.H = int32(float32(sprite.texture.Height) * scale)
+	sprite.updateWordTexture()
+	return sprite
 }
 
-func NewAsteroid(x, y, velocity float32, level int) *Asteroid {
-	asteroid := &Asteroid{}
-	asteroid.alive = true
-	asteroid.destroyed = false
-	asteroid.x = x
-	asteroid.y = y
-	asteroid.velocity = velocity
-	asteroid.targeted = false
-	asteroid.texture = randomAsteroidTexture()
-	asteroid.word = randomWord(level)
-	asteroid.updateWordTexture()
-	return asteroid
+// asteroidKindColor is the tint of the asteroid texture, so each kind of
+// asteroid can be told apart at a glance.
+func asteroidKindColor(kind simulation.AsteroidKind) sdl.Color {
+	switch kind {
+	case simulation.AsteroidSplitter:
+		return asteroidSplitterColor
+	case simulation.AsteroidArmored:
+		return asteroidArmoredColor
+	case simulation.AsteroidFast:
+		return asteroidFastColor
+	case simulation.AsteroidSupply:
+		return asteroidSupplyColor
+	case simulation.AsteroidBoss:
+		return asteroidBossColor
+	}
+	return sdl.Color{R: 255, G: 255, B: 255, A: 255}
 }
 
-func (asteroid *Asteroid) topX() int32 {
-	return int32(asteroid.x) - (asteroid.texture.Width / 2)
+func (sprite *AsteroidSprite) topX(x float32) int32 {
+	return int32(x) + playfieldOffsetX() - (sprite.rectangle.W / 2)
 }
 
-func (asteroid *Asteroid) topY() int32 {
-	return int32(asteroid.y) - (asteroid.texture.Height / 2)
+func (sprite *AsteroidSprite) topY(y float32) int32 {
+	return int32(y) - (sprite.rectangle.H / 2)
 }
 
-func (asteroid *Asteroid) Word() string {
-	return asteroid.word
+// wordPosition places the word to the right of the asteroid, or to its
+// left when there is no room on the right, and keeps it on the screen
+// while the asteroid comes in from a side or swings close to an edge.
+func (sprite *AsteroidSprite) wordPosition() (int32, int32) {
+	edge := asteroidWordPadding + asteroidWordBorder
+	x := sprite.rectangle.X + sprite.rectangle.W + asteroidWordMargin
+	if x+sprite.wordTextureWidth+edge > ScreenWidth {
+		x = sprite.rectangle.X - asteroidWordMargin - sprite.wordTextureWidth
+	}
+	y := sprite.rectangle.Y + (sprite.rectangle.H / 2) - (sprite.wordTextureHeight / 2)
+	if x < edge {
+		x = edge
+	}
+	if x > ScreenWidth-sprite.wordTextureWidth-edge {
+		x = ScreenWidth - sprite.wordTextureWidth - edge
+	}
+	if y < edge {
+		y = edge
+	}
+	if y > ScreenHeight-sprite.wordTextureHeight-edge {
+		y = ScreenHeight - sprite.wordTextureHeight - edge
+	}
+	return x, y
 }
 
-func (asteroid *Asteroid) Damage() int {
-	damage := rand.Intn(asteroidMaxDamage - asteroidMinDamage)
-	damage += asteroidMinDamage
-	return damage
-
+func (sprite *AsteroidSprite) Update(deltaTime float32) {
+	sprite.angle += sprite.angularVelocity * float64(deltaTime)
+	if sprite.texture.Frames > 1 {
+		sprite.frameTimeLeft -= deltaTime
+		for sprite.frameTimeLeft <= 0.0 {
+			sprite.frame = (sprite.frame + 1) % sprite.texture.Frames
+			sprite.frameTimeLeft += sprite.texture.FrameTime
+		}
+	}
 }
-func (asteroid *Asteroid) Destroy() {
-	asteroid.alive = false
-	asteroid.destroyed = true
-	asteroid.explosion = NewExplosionParticleEffect(asteroid.x, asteroid.y)
+
+func (sprite *AsteroidSprite) Destroy() {
+	if sprite.wordTexture != nil {
+		sprite.wordTexture.Destroy()
+		sprite.wordTexture = nil
+	}
 }
 
-func (asteroid *Asteroid) updateWordTexture() {
+func (sprite *AsteroidSprite) updateWordTexture() {
+	sprite.Destroy()
 	color := asteroidRegularWordColor
-	if asteroid.targeted {
+	if sprite.targeted {
 		color = asteroidTargetedWordColor
 	}
-	surface, err := asteroidFont.RenderUTF8Blended(asteroid.word, color)
+	sprite.word = sprite.asteroid.Word()
+	label := sprite.word
+	if sprite.asteroid.Armor() > 0 && sprite.asteroid.Kind() != simulation.AsteroidBoss {
+		label = fmt.Sprintf("%s +%d", label, sprite.asteroid.Armor())
+	}
+	// The space that ends a segment of a boss's sentence has to be typed
+	// too, so it is shown.
+	if strings.HasSuffix(label, " ") {
+		label = strings.TrimSuffix(label, " ") + bossSpaceLabel
+	}
+	surface, err := renderText(asteroidFont, label, color)
 	if err == nil {
-		asteroid.wordTextureWidth = surface.W
-		asteroid.wordTextureHeight = surface.H
-		asteroid.wordTexture, err = applicationRenderer.CreateTextureFromSurface(surface)
+		sprite.wordTextureWidth = surface.W
+		sprite.wordTextureHeight = surface.H
+		sprite.wordTexture, err = applicationRenderer.CreateTextureFromSurface(surface)
 		surface.Free()
 		if err != nil {
-			asteroid.wordTexture = nil
+			sprite.wordTexture = nil
 		}
 	}
 }
 
-func (asteroid *Asteroid) Target() {
-	asteroid.targeted = true
-	asteroid.updateWordTexture()
-}
-
-func (asteroid *Asteroid) Untarget() {
-	asteroid.targeted = false
-	asteroid.updateWordTexture()
-}
-
-func (asteroid *Asteroid) IsAlive() bool {
-	if !asteroid.alive && asteroid.explosion != nil {
-		return asteroid.explosion.IsAlive()
+func (sprite *AsteroidSprite) Draw(renderer *sdl.Renderer, interpolation float32) {
+	if sprite.targeted != sprite.asteroid.IsTargeted() || sprite.word != sprite.asteroid.Word() {
+		sprite.targeted = sprite.asteroid.IsTargeted()
+		sprite.updateWordTexture()
 	}
-	return asteroid.alive
-}
 
-func (asteroid *Asteroid) WasDestroyed() bool {
-	return asteroid.destroyed
-}
-
-func (asteroid *Asteroid) Update(deltaTime float32) {
-	if asteroid.alive {
-		asteroid.y += (asteroid.velocity * deltaTime)
-		if asteroid.topY() > ScreenHeight {
-			asteroid.alive = false
-		}
-	} else {
-		if asteroid.explosion != nil {
-			asteroid.explosion.Update(deltaTime)
-		}
-	}
-}
-
-func (asteroid *Asteroid) Draw(renderer *sdl.Renderer) {
-	if !asteroid.alive {
-		if asteroid.explosion != nil {
-			asteroid.explosion.Draw(renderer)
-			return
-		}
-	}
+	x, y := sprite.asteroid.InterpolatedPosition(interpolation)
+	sprite.rectangle.X = sprite.topX(x)
+	sprite.rectangle.Y = sprite.topY(y)
+	tint := asteroidKindColor(sprite.asteroid.Kind())
+	sprite.texture.Texture.SetColorMod(tint.R, tint.G, tint.B)
+	renderer.CopyEx(sprite.texture.Texture, sprite.texture.Frame(sprite.frame),
+		&sprite.rectangle, sprite.angle, nil, sdl.FLIP_NONE)
 
-	asteroid.rectangle.X = asteroid.topX()
-	asteroid.rectangle.Y = asteroid.topY()
-	asteroid.rectangle.W = asteroid.texture.Width
-	asteroid.rectangle.H = asteroid.texture.Height
-	renderer.Copy(asteroid.texture.Texture, nil, &asteroid.rectangle)
-
-	if asteroid.wordTexture != nil {
+	if sprite.wordTexture != nil {
 		var wordX, wordY int32
 		var bgX, bgY, bgW, bgH int32
 		var borderX, borderY, borderW, borderH int32
-		wordX = asteroid.rectangle.X + asteroid.rectangle.W + asteroidWordMargin
-		wordY = asteroid.rectangle.Y + (asteroid.rectangle.H / 2) - (asteroid.wordTextureHeight / 2)
+		wordX, wordY = sprite.wordPosition()
 		bgX = wordX - asteroidWordPadding
 		bgY = wordY - asteroidWordPadding
-		bgW = asteroid.wordTextureWidth + (asteroidWordPadding * 2)
-		bgH = asteroid.wordTextureHeight + (asteroidWordPadding * 2)
+		bgW = sprite.wordTextureWidth + (asteroidWordPadding * 2)
+		bgH = sprite.wordTextureHeight + (asteroidWordPadding * 2)
 		borderX = bgX - asteroidWordBorder
 		borderY = bgY - asteroidWordBorder
 		borderW = bgW + (asteroidWordBorder * 2)
 		borderH = bgH + (asteroidWordBorder * 2)
 
 		borderColor := asteroidRegularWordColor
-		if asteroid.targeted {
+		if sprite.targeted {
 			borderColor = asteroidTargetedWordColor
 		}
 		renderer.SetDrawColor(borderColor.R, borderColor.G, borderColor.B, 255)
@@ -211,220 +259,200 @@ func (asteroid *Asteroid) Draw(renderer *sdl.Renderer) {
 			H: bgH,
 		})
 		renderer.Copy(
-			asteroid.wordTexture,
+			sprite.wordTexture,
 			nil,
 			&sdl.Rect{
 				X: wordX,
 				Y: wordY,
-				W: asteroid.wordTextureWidth,
-				H: asteroid.wordTextureHeight,
+				W: sprite.wordTextureWidth,
+				H: sprite.wordTextureHeight,
 			},
 		)
 	}
 }
 
-func NewGame() *Game {
-	err := loadAsteroidTextures()
-	if err != nil {
-		panic(err)
+func newGameConfig() simulation.Config {
+	if asteroidTextures == nil {
+		err := loadAsteroidTextures()
+		if err != nil {
+			exitWithError(err)
+		}
 	}
 
-	game := &Game{}
-	return game
+	config := gameConfig
+	config.Width = float32(ScreenWidth)
+	config.Height = float32(ScreenHeight)
+	config.AsteroidVariants = len(asteroidTextures)
+	config.ShipX = config.Width / 2
+	config.ShipY = config.Height + float32(playerOffsetY+playerTextureHeight/2)
+	return config
 }
 
-func (game *Game) Start(asteroidNotDestroyed AsteroidNotDestroyed, nextLevel NextLevel) {
-	game.level = 1
-	game.numberOfAsteroidsToSpawn = startNumberOfAsteroids
-	game.asteroidsLeftToSpawn = game.numberOfAsteroidsToSpawn
-	game.delayBetweenAsteroids = startDelayBetweenAsteroids
-	game.timeUntilNextAsteroidSpawn = game.delayBetweenAsteroids
-	game.asteroidVelocity = startAsteroidVelocity
-	game.asteroidNotDestroyed = asteroidNotDestroyed
-	game.nextLevel = nextLevel
-	game.asteroids = make([]*Asteroid, 0)
+// A game keeps the width it was started with and the screen is never
+// narrower than that, so when the window has been resized since then the
+// playfield is centered on the screen.
+func playfieldOffsetX() int32 {
+	if currentGame == nil {
+		return 0
+	}
+	return (ScreenWidth - int32(currentGame.Config().Width)) / 2
 }
 
-func (game *Game) GetMatchingAsteroid(firstCharacter string) *Asteroid {
-	if len(game.asteroids) > 0 {
-		for _, asteroid := range game.asteroids {
-			if asteroid.IsAlive() {
-				if firstCharacter == string(asteroid.word[0]) {
-					return asteroid
-				}
-			}
-		}
+func resetGameSprites() {
+	for _, sprite := range asteroidSprites {
+		sprite.Destroy()
 	}
-	return nil
+	asteroidSprites = make(map[*simulation.Asteroid]*AsteroidSprite)
+	explosions = make([]*ExplosionParticleEffect, 0)
 }
 
-func (game *Game) spawnNextAsteroid() {
-	x := float32(rand.Intn(int(ScreenWidth)-512) + 64)
-	asteroid := NewAsteroid(x, startAsteroidY, game.asteroidVelocity, game.level)
-	game.asteroids = append(game.asteroids, asteroid)
-	game.asteroidsLeftToSpawn--
+func removeAsteroidSprite(asteroid *simulation.Asteroid) {
+	sprite, ok := asteroidSprites[asteroid]
+	if ok {
+		sprite.Destroy()
+		delete(asteroidSprites, asteroid)
+	}
 }
 
-func (game *Game) goToNextLevel() {
-	game.asteroids = make([]*Asteroid, 0)
-	game.level++
-	game.numberOfAsteroidsToSpawn += asteroidsToSpawnIncrement
-	game.asteroidsLeftToSpawn = game.numberOfAsteroidsToSpawn
-	game.delayBetweenAsteroids += delayBetweenAsteroidsIncrement
-	if game.delayBetweenAsteroids < minDelayBetweenAsteroids {
-		game.delayBetweenAsteroids = minDelayBetweenAsteroids
-	}
-	game.timeUntilNextAsteroidSpawn = game.delayBetweenAsteroids
-	game.asteroidVelocity += asteroidVelocityIncrement
-	if game.nextLevel != nil {
-		game.nextLevel(game.level)
-	}
+func explodeAsteroid(asteroid *simulation.Asteroid) {
+	removeAsteroidSprite(asteroid)
+	crackAsteroid(asteroid)
 }
 
-func (game *Game) Update(deltaTime float32) {
-	if game.asteroidsLeftToSpawn > 0 {
-		game.timeUntilNextAsteroidSpawn -= deltaTime
-		if game.timeUntilNextAsteroidSpawn <= 0.0 {
-			game.spawnNextAsteroid()
-
-			var leftOverTime float32 = 0.0
-			if game.timeUntilNextAsteroidSpawn < 0.0 {
-				leftOverTime = game.timeUntilNextAsteroidSpawn
-			}
-			game.timeUntilNextAsteroidSpawn = game.delayBetweenAsteroids + leftOverTime
-		}
+// crackAsteroid shows an explosion where the asteroid is without removing
+// it, for armored asteroids that lose a layer of armor.
+func crackAsteroid(asteroid *simulation.Asteroid) {
+	x := asteroid.X() + float32(playfieldOffsetX())
+	explosions = append(explosions, NewExplosionParticleEffect(x, asteroid.Y()))
+	soundExplosion.PlayAt(x)
+}
+
+func asteroidSprite(asteroid *simulation.Asteroid) *AsteroidSprite {
+	sprite, ok := asteroidSprites[asteroid]
+	if !ok {
+		sprite = NewAsteroidSprite(asteroid)
+		asteroidSprites[asteroid] = sprite
 	}
+	return sprite
+}
 
-	allAsteroidsDead := true
-	for _, asteroid := range game.asteroids {
+// updateAsteroidSprites turns the asteroids once per simulation step, so
+// that a replay shows them as they were when it was recorded.
+func updateAsteroidSprites(deltaTime float32) {
+	for _, asteroid := range currentGame.Asteroids() {
 		if asteroid.IsAlive() {
-			asteroid.Update(deltaTime)
-			if asteroid.IsAlive() {
-				allAsteroidsDead = false
-			} else if !asteroid.WasDestroyed() {
-				if game.asteroidNotDestroyed != nil {
-					game.asteroidNotDestroyed(asteroid.Damage())
-				}
-			}
+			asteroidSprite(asteroid).Update(deltaTime)
 		}
 	}
-
-	if allAsteroidsDead && game.asteroidsLeftToSpawn <= 0 {
-		game.goToNextLevel()
-	}
 }
 
-func (game *Game) Draw(renderer *sdl.Renderer) {
-	for _, asteroid := range game.asteroids {
-		if asteroid.IsAlive() {
-			asteroid.Draw(renderer)
+func updateExplosions(deltaTime float32) {
+	alive := explosions[:0]
+	for _, explosion := range explosions {
+		explosion.Update(deltaTime)
+		if explosion.IsAlive() {
+			alive = append(alive, explosion)
 		}
 	}
+	explosions = alive
 }
 
-func createWordList() {
-	wordList = append(wordList, "car")
-	wordList = append(wordList, "eat")
-	wordList = append(wordList, "fat")
-	wordList = append(wordList, "gun")
-	wordList = append(wordList, "hug")
-	wordList = append(wordList, "net")
-	wordList = append(wordList, "put")
-	wordList = append(wordList, "war")
-
-	wordList = append(wordList, "five")
-	wordList = append(wordList, "four")
-	wordList = append(wordList, "nine")
-	wordList = append(wordList, "bear")
-	wordList = append(wordList, "food")
-	wordList = append(wordList, "last")
-	wordList = append(wordList, "fast")
-	wordList = append(wordList, "port")
-	wordList = append(wordList, "door")
-
-	wordList = append(wordList, "seven")
-	wordList = append(wordList, "eight")
-	wordList = append(wordList, "right")
-	wordList = append(wordList, "smite")
-	wordList = append(wordList, "queue")
-	wordList = append(wordList, "smart")
-	wordList = append(wordList, "smear")
-	wordList = append(wordList, "dance")
-	wordList = append(wordList, "blast")
-
-	wordList = append(wordList, "eleven")
-	wordList = append(wordList, "twelve")
-	wordList = append(wordList, "tought")
-	wordList = append(wordList, "bought")
-	wordList = append(wordList, "trench")
-	wordList = append(wordList, "cought")
-	wordList = append(wordList, "faster")
-	wordList = append(wordList, "answer")
-	wordList = append(wordList, "slower")
-
-	wordList = append(wordList, "monster")
-	wordList = append(wordList, "bouncer")
-	wordList = append(wordList, "assault")
-	wordList = append(wordList, "message")
-	wordList = append(wordList, "corrupt")
-	wordList = append(wordList, "acquire")
-	wordList = append(wordList, "explodes")
-	wordList = append(wordList, "contains")
-	wordList = append(wordList, "tailoring")
-
-	wordList = append(wordList, "sacrifice")
-	wordList = append(wordList, "feedback")
-	wordList = append(wordList, "purchase")
-	wordList = append(wordList, "financial")
-	wordList = append(wordList, "difficult")
-	wordList = append(wordList, "department")
-	wordList = append(wordList, "exchange")
-	wordList = append(wordList, "exhibiting")
-	wordList = append(wordList, "dedication")
-	wordList = append(wordList, "complicated")
+func drawGame(renderer *sdl.Renderer, interpolation float32) {
+	for _, asteroid := range currentGame.Asteroids() {
+		if !asteroid.IsAlive() {
+			continue
+		}
+		asteroidSprite(asteroid).Draw(renderer, interpolation)
+	}
+	for _, explosion := range explosions {
+		explosion.Draw(renderer)
+	}
+	drawBossProgress(renderer)
 }
 
-func init() {
-	createWordList()
+// drawBossProgress shows the sentence of the boss at the top of the screen
+// with a bar below it that fills up as the sentence is typed.
+func drawBossProgress(renderer *sdl.Renderer) {
+	boss := currentGame.Boss()
+	if boss == nil {
+		return
+	}
+	if bossSentence == nil {
+		bossSentence = NewText(fontPath, hudFontSize)
+	}
+	if bossShown != boss {
+		bossShown = boss
+		bossSentence.Update(boss.Sentence(), applicationRenderer)
+	}
+	y := bossBarMarginTop
+	bossSentence.Draw(renderer, (ScreenWidth/2)-(bossSentence.Width()/2), y)
+	y += bossSentence.Height() + 8
+
+	x := (ScreenWidth / 2) - (bossBarWidth / 2)
+	renderer.SetDrawColor(asteroidBossColor.R, asteroidBossColor.G, asteroidBossColor.B, 255)
+	renderer.FillRect(&sdl.Rect{X: x - 1, Y: y - 1, W: bossBarWidth + 2, H: bossBarHeight + 2})
+	renderer.SetDrawColor(0, 43, 54, 255)
+	renderer.FillRect(&sdl.Rect{X: x, Y: y, W: bossBarWidth, H: bossBarHeight})
+	renderer.SetDrawColor(asteroidBossColor.R, asteroidBossColor.G, asteroidBossColor.B, 255)
+	renderer.FillRect(&sdl.Rect{X: x, Y: y, W: int32(float32(bossBarWidth) * boss.Progress()), H: bossBarHeight})
 }
 
+// loadAsteroidTextures loads every PNG image in the asteroid directory, so
+// adding a rock is a matter of dropping its image there.
 func loadAsteroidTextures() error {
-	texturePaths := []string{
-		asteroid1TexturePath,
-		asteroid2TexturePath,
-		asteroid3TexturePath,
-		asteroid4TexturePath,
-	}
-	for _, texturePath := range texturePaths {
-		surface, err := img.Load(texturePath)
-		if err != nil {
-			return err
+	entries, err := fs.ReadDir(resources, asteroidTextureDirectory)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.ToLower(path.Ext(entry.Name())) != ".png" {
+			continue
 		}
-		defer surface.Free()
-		texture := new(AsteroidTexture)
-		texture.Width = surface.W
-		texture.Height = surface.H
-		texture.Texture, err = applicationRenderer.CreateTextureFromSurface(surface)
+		texture, err := loadAsteroidTexture(path.Join(asteroidTextureDirectory, entry.Name()))
 		if err != nil {
 			return err
 		}
 		asteroidTextures = append(asteroidTextures, texture)
 	}
+	if len(asteroidTextures) == 0 {
+		return fmt.Errorf("%s: no asteroid images found", asteroidTextureDirectory)
+	}
 	return nil
 }
 
-func randomAsteroidTexture() *AsteroidTexture {
-	max := len(asteroidTextures) - 1
-	random := rand.Intn(max)
-	return asteroidTextures[random]
-}
+func loadAsteroidTexture(texturePath string) (*AsteroidTexture, error) {
+	sheet := AsteroidSheet{Columns: 1, Rows: 1}
+	sheetPath := strings.TrimSuffix(texturePath, path.Ext(texturePath)) + ".json"
+	data, err := resources.ReadFile(sheetPath)
+	if err == nil {
+		err = json.Unmarshal(data, &sheet)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", sheetPath, err)
+		}
+		if sheet.Columns < 1 || sheet.Rows < 1 {
+			return nil, fmt.Errorf("%s: a sprite sheet needs at least one column and row", sheetPath)
+		}
+		if sheet.Columns*sheet.Rows > 1 && sheet.FrameTime <= 0 {
+			return nil, fmt.Errorf("%s: frameTime must be greater than 0, got %v", sheetPath, sheet.FrameTime)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
 
-func randomWord(level int) string {
-	level--
-	if level > 20 {
-		level = 20
+	surface, err := resources.Surface(texturePath)
+	if err != nil {
+		return nil, err
+	}
+	defer surface.Free()
+	texture := new(AsteroidTexture)
+	texture.Width = surface.W / int32(sheet.Columns)
+	texture.Height = surface.H / int32(sheet.Rows)
+	texture.Columns = sheet.Columns
+	texture.Frames = sheet.Columns * sheet.Rows
+	texture.FrameTime = sheet.FrameTime
+	texture.Texture, err = applicationRenderer.CreateTextureFromSurface(surface)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", texturePath, err)
 	}
-	max := len(wordList) - 1
-	random := rand.Intn(max-level) + level
-	return wordList[random]
+	return texture, nil
 }
@@ -0,0 +1,5 @@
+module github.com/snosscire/astrotyper
+
+go 1.16
+
+require github.com/veandco/go-sdl2 v0.4.39
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	highScoreFileName      string = "highscores.json"
+	highScoreTableSize     int    = 10
+	highScoreNameMaxLength int    = 16
+	highScoreFontSize      int    = 28
+	highScoreTitleFontSize int    = 64
+	highScoreRowSpacing    int32  = 8
+
+	highScores        []HighScore
+	highScoresShown   bool
+	highScoreNewEntry int = -1
+
+	nameEntry     bool
+	nameEntryText string
+
+	highScoreTitle *Text
+	highScoreRows  []*Text
+	highScoreHint  *Text
+	overlayName    *Text
+)
+
+type HighScore struct {
+	Name           string    `json:"name"`
+	Score          int       `json:"score"`
+	Level          int       `json:"level"`
+	WordsPerMinute float64   `json:"wpm"`
+	Accuracy       float64   `json:"accuracy"`
+	Seed           int64     `json:"seed"`
+	Date           time.Time `json:"date"`
+}
+
+func dataDirectory() (string, error) {
+	directory := os.Getenv("XDG_DATA_HOME")
+	if directory == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		directory = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(directory, "astrotyper"), nil
+}
+
+func highScorePath() (string, error) {
+	directory, err := dataDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(directory, highScoreFileName), nil
+}
+
+func loadHighScores() ([]HighScore, error) {
+	path, err := highScorePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return make([]HighScore, 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	scores := make([]HighScore, 0)
+	err = json.Unmarshal(data, &scores)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return scores, nil
+}
+
+func saveHighScores(scores []HighScore) error {
+	path, err := highScorePath()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(scores, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func isHighScore(score int) bool {
+	if score <= 0 {
+		return false
+	}
+	if len(highScores) < highScoreTableSize {
+		return true
+	}
+	return score > highScores[len(highScores)-1].Score
+}
+
+func addHighScore(entry HighScore) int {
+	highScores = append(highScores, entry)
+	sort.SliceStable(highScores, func(i, j int) bool {
+		return highScores[i].Score > highScores[j].Score
+	})
+	if len(highScores) > highScoreTableSize {
+		highScores = highScores[:highScoreTableSize]
+	}
+	for i := range highScores {
+		if highScores[i] == entry {
+			return i
+		}
+	}
+	return -1
+}
+
+func initHighScores() {
+	var err error
+	highScores, err = loadHighScores()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load high scores: %v\n", err)
+		highScores = make([]HighScore, 0)
+	}
+}
+
+func startNameEntry() {
+	nameEntry = true
+	nameEntryText = ""
+	updateNameEntry()
+}
+
+func updateNameEntry() {
+	if overlayName == nil {
+		overlayName = NewText(fontPath, hudFontSize)
+	}
+	overlayName.Update("New high score! Your name: "+nameEntryText+"_", applicationRenderer)
+}
+
+func handleNameEntryKey(t *sdl.KeyboardEvent) {
+	key := t.Keysym.Sym
+	if key == sdl.K_RETURN {
+		if len(nameEntryText) > 0 {
+			finishNameEntry()
+		}
+	} else if key == sdl.K_BACKSPACE {
+		if len(nameEntryText) > 0 {
+			nameEntryText = nameEntryText[:len(nameEntryText)-1]
+			updateNameEntry()
+		}
+	}
+}
+
+func handleNameEntryText(text string) {
+	for _, character := range text {
+		if len(nameEntryText) >= highScoreNameMaxLength {
+			break
+		}
+		if (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z') ||
+			(character >= '0' && character <= '9') || character == ' ' {
+			nameEntryText += string(character)
+		}
+	}
+	updateNameEntry()
+}
+
+func finishNameEntry() {
+	nameEntry = false
+	highScoreNewEntry = addHighScore(HighScore{
+		Name:           nameEntryText,
+		Score:          currentGame.Score(),
+		Level:          currentGame.Level(),
+		WordsPerMinute: currentGame.WordsPerMinute(),
+		Accuracy:       currentGame.Accuracy(),
+		Seed:           currentGame.Seed(),
+		Date:           time.Now(),
+	})
+	err := saveHighScores(highScores)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not save high scores: %v\n", err)
+	}
+	showHighScores()
+}
+
+func showHighScores() {
+	highScoresShown = true
+	gameOver = false
+	mainMenu = true
+
+	if highScoreTitle == nil {
+		highScoreTitle = NewText(fontPath, highScoreTitleFontSize)
+	}
+	highScoreTitle.Update("High Scores", applicationRenderer)
+	if highScoreHint == nil {
+		highScoreHint = NewText(fontPath, highScoreFontSize)
+	}
+	highScoreHint.Update("Press Enter to return to the menu", applicationRenderer)
+
+	for len(highScoreRows) < highScoreTableSize {
+		highScoreRows = append(highScoreRows, NewText(fontPath, highScoreFontSize))
+	}
+	for i, row := range highScoreRows {
+		text := fmt.Sprintf("%2d. ---", i+1)
+		if i < len(highScores) {
+			entry := highScores[i]
+			marker := "  "
+			if i == highScoreNewEntry {
+				marker = "> "
+			}
+			text = fmt.Sprintf("%s%2d. %-16s %8d  level %-3d %5.1f wpm %5.1f%%  %s  seed %d",
+				marker, i+1, entry.Name, entry.Score, entry.Level, entry.WordsPerMinute,
+				entry.Accuracy*100.0, entry.Date.Format("2006-01-02"), entry.Seed)
+		}
+		row.Update(text, applicationRenderer)
+	}
+}
+
+func hideHighScores() {
+	highScoresShown = false
+	highScoreNewEntry = -1
+}
+
+func drawHighScores() {
+	y := ScreenHeight / 8
+	highScoreTitle.Draw(applicationRenderer, (ScreenWidth/2)-(highScoreTitle.Width()/2), y)
+	y += highScoreTitle.Height() * 2
+	x := (ScreenWidth / 2) - (highScoreRows[0].Width() / 2)
+	for _, row := range highScoreRows {
+		if row.Width() > 0 && (ScreenWidth/2)-(row.Width()/2) < x {
+			x = (ScreenWidth / 2) - (row.Width() / 2)
+		}
+	}
+	for _, row := range highScoreRows {
+		row.Draw(applicationRenderer, x, y)
+		y += row.Height() + highScoreRowSpacing
+	}
+	y += highScoreTitle.Height()
+	highScoreHint.Draw(applicationRenderer, (ScreenWidth/2)-(highScoreHint.Width()/2), y)
+}
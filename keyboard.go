@@ -0,0 +1,196 @@
+package main
+
+import (
+	"unicode"
+
+	"github.com/snosscire/astrotyper/simulation"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	keyboardKeySize         int32   = 40
+	keyboardKeyGap          int32   = 4
+	keyboardMargin          int32   = 12
+	keyboardWidthKeys       float32 = 15.0
+	keyboardFontSize        int     = 20
+	keyboardAlpha           uint8   = 96
+	keyboardBackgroundAlpha uint8   = 128
+	keyboardNextAlpha       uint8   = 220
+	keyboardFlashTime       float32 = 300.0
+
+	keyboardBackgroundColor sdl.Color = sdl.Color{R: 0, G: 43, B: 54, A: 255}
+	keyboardMistypeColor    sdl.Color = sdl.Color{R: 220, G: 50, B: 47, A: 255}
+
+	// keyboardFingerColors tints the keys of each finger, from the left
+	// little finger to the right little finger. Both hands use the same
+	// colors, so the same finger of either hand looks alike.
+	keyboardFingerColors []sdl.Color = []sdl.Color{
+		{R: 211, G: 54, B: 130, A: 255},
+		{R: 108, G: 113, B: 196, A: 255},
+		{R: 38, G: 139, B: 210, A: 255},
+		{R: 133, G: 153, B: 0, A: 255},
+		{R: 147, G: 161, B: 161, A: 255},
+		{R: 147, G: 161, B: 161, A: 255},
+		{R: 133, G: 153, B: 0, A: 255},
+		{R: 38, G: 139, B: 210, A: 255},
+		{R: 108, G: 113, B: 196, A: 255},
+		{R: 211, G: 54, B: 130, A: 255},
+	}
+
+	keyboardLayout    *simulation.KeyboardLayout
+	keyboardLabels    map[*simulation.Key]*Text
+	keyboardFlashKey  *simulation.Key
+	keyboardFlashLeft float32
+)
+
+// setKeyboardLayout prepares the labels of the on-screen keyboard for the
+// layout of a new game.
+func setKeyboardLayout(layout *simulation.KeyboardLayout) {
+	keyboardFlashKey = nil
+	keyboardFlashLeft = 0.0
+	if layout == keyboardLayout {
+		return
+	}
+	keyboardLayout = layout
+	keyboardLabels = make(map[*simulation.Key]*Text)
+	for _, key := range layout.Keys {
+		if key.Character == ' ' {
+			continue
+		}
+		label := string(key.Character)
+		if key.Shifted == unicode.ToUpper(key.Character) {
+			label = string(key.Shifted)
+		}
+		text := NewText(fontPath, keyboardFontSize)
+		text.Update(label, applicationRenderer)
+		keyboardLabels[key] = text
+	}
+}
+
+// flashKeyboard shows a mistype on the on-screen keyboard, on the key of
+// the wrong character when the layout has it.
+func flashKeyboard(character rune) {
+	if keyboardLayout == nil {
+		return
+	}
+	keyboardFlashKey, _ = keyboardLayout.Key(character)
+	keyboardFlashLeft = keyboardFlashTime
+}
+
+func updateKeyboard(deltaTime float32) {
+	if keyboardFlashLeft > 0.0 {
+		keyboardFlashLeft -= deltaTime
+	}
+}
+
+func keyboardUnit() float32 {
+	return float32(keyboardKeySize + keyboardKeyGap)
+}
+
+func keyboardHeight() int32 {
+	return int32(float32(keyboardLayout.Rows)*keyboardUnit()) - keyboardKeyGap
+}
+
+// keyboardTop is where the on-screen keyboard starts, just above the box
+// with the typed word.
+func keyboardTop() int32 {
+	return currentWordBackground().Y - keyboardMargin - keyboardHeight()
+}
+
+func keyboardShown() bool {
+	return settings.OnScreenKeyboard && keyboardLayout != nil && !gameOver
+}
+
+func keyboardRect(x, width float32, row int) *sdl.Rect {
+	unit := keyboardUnit()
+	left := float32(ScreenWidth)/2 - keyboardWidthKeys*unit/2
+	return &sdl.Rect{
+		X: int32(left + x*unit),
+		Y: keyboardTop() + int32(float32(row)*unit),
+		W: int32(width*unit) - keyboardKeyGap,
+		H: keyboardKeySize,
+	}
+}
+
+func fillKeyboardRect(rect *sdl.Rect, color sdl.Color, alpha uint8) {
+	applicationRenderer.SetDrawColor(color.R, color.G, color.B, alpha)
+	applicationRenderer.FillRect(rect)
+}
+
+// shiftKeyRects returns the left and right shift keys, which fill the
+// bottom row of letters out to the edges of the keyboard.
+func shiftKeyRects() (*sdl.Rect, *sdl.Rect) {
+	row := keyboardLayout.Rows - 2
+	first, last := keyboardWidthKeys, float32(0.0)
+	for _, key := range keyboardLayout.Keys {
+		if key.Row != row {
+			continue
+		}
+		if key.X < first {
+			first = key.X
+		}
+		if key.X+key.Width > last {
+			last = key.X + key.Width
+		}
+	}
+	return keyboardRect(0.0, first, row), keyboardRect(last, keyboardWidthKeys-last, row)
+}
+
+// drawKeyboard draws a translucent keyboard of the chosen layout with the
+// keys tinted by finger. The key for the next character of the targeted
+// word stands out in the color of its finger, together with the shift key
+// to hold, and a wrong key flashes red.
+func drawKeyboard() {
+	if !keyboardShown() {
+		return
+	}
+	applicationRenderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+
+	next, shift := keyboardLayout.Key(currentGame.NextCharacter())
+	flash := keyboardFlashLeft > 0.0
+	for _, key := range keyboardLayout.Keys {
+		rect := keyboardRect(key.X, key.Width, key.Row)
+		fillKeyboardRect(rect, keyboardBackgroundColor, keyboardBackgroundAlpha)
+		color := keyboardFingerColors[key.Finger]
+		alpha := keyboardAlpha
+		if flash && key == keyboardFlashKey {
+			color = keyboardMistypeColor
+			alpha = keyboardNextAlpha
+		} else if key == next {
+			alpha = keyboardNextAlpha
+		}
+		fillKeyboardRect(rect, color, alpha)
+	}
+
+	leftShift, rightShift := shiftKeyRects()
+	for hand, rect := range []*sdl.Rect{leftShift, rightShift} {
+		fillKeyboardRect(rect, keyboardBackgroundColor, keyboardBackgroundAlpha)
+		color := keyboardFingerColors[0]
+		if hand == 1 {
+			color = keyboardFingerColors[len(keyboardFingerColors)-1]
+		}
+		alpha := keyboardAlpha
+		if shift && next != nil && keyboardLayout.Hand(next.Character) != hand {
+			alpha = keyboardNextAlpha
+		}
+		fillKeyboardRect(rect, color, alpha)
+	}
+
+	if flash {
+		top := keyboardTop()
+		outline := keyboardRect(0.0, keyboardWidthKeys, 0)
+		outline.X -= keyboardKeyGap
+		outline.Y = top - keyboardKeyGap
+		outline.W += keyboardKeyGap * 2
+		outline.H = keyboardHeight() + keyboardKeyGap*2
+		applicationRenderer.SetDrawColor(keyboardMistypeColor.R, keyboardMistypeColor.G,
+			keyboardMistypeColor.B, keyboardNextAlpha)
+		applicationRenderer.DrawRect(outline)
+	}
+	applicationRenderer.SetDrawBlendMode(sdl.BLENDMODE_NONE)
+
+	for key, label := range keyboardLabels {
+		rect := keyboardRect(key.X, key.Width, key.Row)
+		label.Draw(applicationRenderer, rect.X+(rect.W-label.Width())/2, rect.Y+(rect.H-label.Height())/2)
+	}
+}
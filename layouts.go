@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/snosscire/astrotyper/simulation"
+	"github.com/snosscire/astrotyper/stats"
+)
+
+var (
+	keyboardLayoutDirectory string = "resources/layouts"
+
+	fingerHintMargin int32 = 12
+
+	fingerHint          *Text
+	fingerHintCharacter rune
+	fingerHintShown     bool
+)
+
+// initKeyboardLayouts adds the layouts defined in resources/layouts to
+// the presets, so that they can be picked under Settings.
+func initKeyboardLayouts() {
+	layouts, err := simulation.LoadKeyboardLayouts(resources, keyboardLayoutDirectory)
+	if err != nil {
+		exitWithError(err)
+	}
+	for _, layout := range layouts {
+		err = simulation.AddKeyboardLayout(layout)
+		if err != nil {
+			exitWithError(err)
+		}
+	}
+}
+
+// fingerHintText tells which finger types the character on the layout,
+// and which shift key to hold with the other hand when it is shifted.
+func fingerHintText(layout *simulation.KeyboardLayout, character rune) string {
+	key, shift := layout.Key(character)
+	if key == nil {
+		return ""
+	}
+	name := string(character)
+	if character == ' ' {
+		name = "space"
+	}
+	text := fmt.Sprintf("%s: %s", name, stats.FingerName(key.Finger))
+	if shift {
+		if layout.Hand(character) == 0 {
+			text += " + right shift"
+		} else {
+			text += " + left shift"
+		}
+	}
+	return text
+}
+
+func resetFingerHint() {
+	fingerHintCharacter = 0
+	fingerHintShown = false
+}
+
+func updateFingerHint() {
+	if !(settings.FingerHints || settings.OnScreenKeyboard) || gameOver {
+		fingerHintShown = false
+		return
+	}
+	character := currentGame.NextCharacter()
+	if character == fingerHintCharacter {
+		return
+	}
+	fingerHintCharacter = character
+	text := ""
+	if character != 0 {
+		text = fingerHintText(currentGame.Layout(), character)
+	}
+	fingerHintShown = text != ""
+	if !fingerHintShown {
+		return
+	}
+	if fingerHint == nil {
+		fingerHint = NewText(fontPath, hudFontSize)
+	}
+	fingerHint.Update(text, applicationRenderer)
+}
+
+func drawFingerHint() {
+	if !fingerHintShown {
+		return
+	}
+	top := currentWordBackground().Y
+	if keyboardShown() {
+		top = keyboardTop()
+	}
+	x := (ScreenWidth / 2) - (fingerHint.Width() / 2)
+	y := top - fingerHint.Height() - fingerHintMargin
+	fingerHint.Draw(applicationRenderer, x, y)
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math/rand"
+	"os"
 	"runtime"
+	"strconv"
 	"time"
 
+	"github.com/snosscire/astrotyper/simulation"
 	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/mix"
 	"github.com/veandco/go-sdl2/sdl"
@@ -22,7 +26,7 @@ var (
 	asteroidFont     *ttf.Font
 
 	menuItemFontSize int = 42
-	menuItemSelected int = 0
+	mainMenuRoot     *Menu
 
 	currentWordWidth    int32 = 350
 	currentWordHeight   int32 = 37
@@ -44,12 +48,14 @@ var (
 
 	overlayGameOver *Text
 	overlayScore    *Text
+	overlaySeed     *Text
 	overlayLevel    *Text
 	hudEarth        *Text
 	hudScore        *Text
-	menuItemStart   *Text
-	menuItemQuit    *Text
+	hudCombo        *Text
+	overlayHint     *Text
 
+	menuLogoTexturePath            string = "resources/menu/logo.png"
 	menuLogoTexture                *sdl.Texture
 	menuLogoTextureWidth           int32
 	menuLogoTextureHeight          int32
@@ -66,16 +72,26 @@ var (
 	hudMarginRight  int32 = 16
 	hudMarginBottom int32 = 8
 
+	simulationStepTime    float32 = 1000.0 / 120.0
+	simulationAccumulator float32
+	maxFrameTime          float32 = 250.0
+	frameRateLimit        int
+	verticalSync          bool
+
 	levelFontSize   int     = 92
 	levelTimeToShow float32 = 2500.0
 	levelTimeLeft   float32
 
-	currentGame     *Game
-	currentPlayer   *Player
-	currentWord     string
-	currentAsteroid *Asteroid
+	currentGame   *simulation.Game
+	currentPlayer *Player
+	currentWord   string
+
+	// currentComposition is the text an input method is still composing,
+	// which is shown after the typed word until it is committed.
+	currentComposition string
 
-	playerScore int
+	gameSeed    int64
+	gameSeedSet bool
 )
 
 func handleEvents() {
@@ -83,164 +99,356 @@ func handleEvents() {
 		switch t := event.(type) {
 		case *sdl.QuitEvent:
 			applicationRunning = false
+		case *sdl.WindowEvent:
+			handleWindowEvent(t)
 		case *sdl.KeyboardEvent:
-			if t.Type == sdl.KEYUP {
+			if isFullscreenKey(t) {
+				if t.Type == sdl.KEYDOWN {
+					toggleFullscreen()
+				}
 				continue
 			}
-			if t.Keysym.Sym == sdl.K_ESCAPE {
-				if mainMenu {
-					applicationRunning = false
-				} else {
-					if len(currentWord) > 0 {
-						currentWord = ""
-						if currentAsteroid != nil {
-							currentAsteroid.Untarget()
-							currentAsteroid = nil
-						}
-						updateCurrentWordTexture()
-					} else {
-						mainMenu = true
-						gameOver = false
-					}
-				}
-			} else if t.Keysym.Sym == sdl.K_BACKSPACE {
-				if len(currentWord) > 0 {
-					index := len(currentWord) - 1
-					currentWord = currentWord[:index]
-					updateCurrentWordTexture()
-				}
-			} else if t.Keysym.Sym == sdl.K_UP {
-				if mainMenu {
-					if menuItemSelected == 0 {
-						menuItemSelected = 1
-					} else {
-						menuItemSelected = 0
-					}
-					createMainMenu()
-				}
-			} else if t.Keysym.Sym == sdl.K_DOWN {
-				if mainMenu {
-					if menuItemSelected == 0 {
-						menuItemSelected = 1
-					} else {
-						menuItemSelected = 0
-					}
-					createMainMenu()
+			if isMuteKey(t) {
+				if t.Type == sdl.KEYDOWN {
+					toggleMusicMuted()
 				}
-			} else if t.Keysym.Sym == sdl.K_RETURN {
-				if mainMenu {
-					if menuItemSelected == 0 {
-						startGame()
-						mainMenu = false
-						gameOver = false
-					} else if menuItemSelected == 1 {
-						applicationRunning = false
-					}
-				}
-			} else {
-				if gameOver || gamePaused {
-					return
+				continue
+			}
+			if replayPlaying != nil && !mainMenu {
+				if t.Type == sdl.KEYDOWN && t.Keysym.Sym == sdl.K_ESCAPE {
+					stopReplay()
 				}
-				key := int(t.Keysym.Sym)
-				if key >= 97 && key <= 122 {
-					character := string(rune(key))
-					if len(currentWord) == 0 {
-						asteroid := currentGame.GetMatchingAsteroid(character)
-						if asteroid != nil {
-							currentAsteroid = asteroid
-							currentAsteroid.Target()
-							currentWord += character
-							updateCurrentWordTexture()
-						}
-					} else {
-						word := currentAsteroid.Word()
-						wordLen := len(word)
-						currentWordLen := len(currentWord)
-						if currentWordLen < wordLen {
-							nextValid := string(word[currentWordLen])
-							if character == nextValid {
-								currentWord += character
-								updateCurrentWordTexture()
-								if len(currentWord) == wordLen {
-									currentAsteroid.Destroy()
-									playerScore += (len(currentAsteroid.word) * currentGame.level) * 10
-									hudScore.Update(fmt.Sprintf("Score: %d", playerScore), applicationRenderer)
-									currentAsteroid = nil
-									currentWord = ""
-									updateCurrentWordTexture()
-								}
-							}
-						}
-					}
+				continue
+			}
+			if currentComposition != "" {
+				// Keys such as backspace and return edit the
+				// composition of the input method.
+				continue
+			}
+			if t.Type == sdl.KEYDOWN && !mainMenu && !gameOver && !gamePaused {
+				recordKeyboardEvent(t)
+			}
+			handleKeyboardEvent(t)
+		case *sdl.TextInputEvent:
+			if replayPlaying != nil && !mainMenu {
+				continue
+			}
+			if !mainMenu && !gameOver && !gamePaused {
+				recordTextInputEvent(t)
+			}
+			currentComposition = ""
+			handleTextInput(t.GetText())
+		case *sdl.TextEditingEvent:
+			if currentComposition != t.GetText() {
+				currentComposition = t.GetText()
+				if !mainMenu {
+					updateCurrentWordTexture()
 				}
 			}
 		}
 	}
 }
 
-func handleAsteroidNotDestroyed(damage int) {
-	currentPlayer.TakeDamage(damage)
-	text := fmt.Sprintf("Earth: %d%%", currentPlayer.CurrentHealth())
+func handleKeyboardEvent(t *sdl.KeyboardEvent) {
+	if t.Type == sdl.KEYUP {
+		return
+	}
+	if highScoresShown {
+		if t.Keysym.Sym == sdl.K_ESCAPE || t.Keysym.Sym == sdl.K_RETURN {
+			hideHighScores()
+			showMainMenu()
+		}
+		return
+	}
+	if gameOver && nameEntry {
+		if t.Keysym.Sym == sdl.K_ESCAPE {
+			nameEntry = false
+			showMainMenu()
+		} else {
+			handleNameEntryKey(t)
+		}
+		return
+	}
+	if gamePaused {
+		handlePauseKey(t)
+		return
+	}
+	if mainMenu {
+		handleMenuKey(t.Keysym.Sym)
+		return
+	}
+	if t.Keysym.Sym == sdl.K_ESCAPE {
+		if gameOver {
+			showMainMenu()
+		} else if len(currentGame.Input()) > 0 {
+			currentGame.ClearInput()
+		} else {
+			pauseGame()
+		}
+	} else if isPauseKey(t) {
+		pauseGame()
+	} else if t.Keysym.Sym == sdl.K_TAB {
+		if !gameOver {
+			currentGame.CycleTarget()
+		}
+	} else if t.Keysym.Sym == sdl.K_BACKSPACE {
+		currentGame.Backspace()
+	} else if t.Keysym.Sym == sdl.K_RETURN {
+		if gameOver {
+			showHighScores()
+		}
+	}
+}
+
+// handleTextInput types the text entered with the keyboard, which already
+// has shift, caps lock and the keyboard layout applied by SDL.
+func handleTextInput(text string) {
+	if gameOver && nameEntry {
+		handleNameEntryText(text)
+		return
+	}
+	if highScoresShown || gamePaused || mainMenu || gameOver {
+		return
+	}
+	for _, character := range text {
+		if currentGame.Type(character) {
+			soundKeystroke.Play()
+		}
+	}
+}
+
+func handleAsteroidDestroyed(asteroid *simulation.Asteroid) {
+	explodeAsteroid(asteroid)
+	if asteroid.Kind() == simulation.AsteroidSupply {
+		soundLevelUp.Play()
+		updateMusicIntensity()
+		text := fmt.Sprintf("Earth: %d%%", currentGame.Player().CurrentHealth())
+		hudEarth.Update(text, applicationRenderer)
+	}
+	hudScore.Update(fmt.Sprintf("Score: %d", currentGame.Score()), applicationRenderer)
+	updateHUDCombo()
+}
+
+func handleAsteroidCracked(asteroid *simulation.Asteroid) {
+	crackAsteroid(asteroid)
+	hudScore.Update(fmt.Sprintf("Score: %d", currentGame.Score()), applicationRenderer)
+	updateHUDCombo()
+}
+
+func handleMistype(character rune) {
+	soundMistype.Play()
+	flashKeyboard(character)
+	updateMusicIntensity()
+	updateHUDCombo()
+	text := fmt.Sprintf("Earth: %d%%", currentGame.Player().CurrentHealth())
+	hudEarth.Update(text, applicationRenderer)
+}
+
+func updateHUDCombo() {
+	text := fmt.Sprintf("Combo: %d  x%d", currentGame.Combo(), currentGame.Multiplier())
+	hudCombo.Update(text, applicationRenderer)
+}
+
+func handleTargetLocked(asteroid *simulation.Asteroid) {
+	soundLock.PlayAt(asteroid.X() + float32(playfieldOffsetX()))
+}
+
+func handleAsteroidNotDestroyed(asteroid *simulation.Asteroid, damage int) {
+	removeAsteroidSprite(asteroid)
+	if damage > 0 {
+		soundImpact.PlayAt(asteroid.X() + float32(playfieldOffsetX()))
+	}
+	updateMusicIntensity()
+	text := fmt.Sprintf("Earth: %d%%", currentGame.Player().CurrentHealth())
 	hudEarth.Update(text, applicationRenderer)
+}
 
-	if currentPlayer.CurrentHealth() == 0 {
-		gameOver = true
-		levelTimeLeft = 0.0
-		overlayScore.Update(fmt.Sprintf("Your score: %d", playerScore), applicationRenderer)
+func handleGameOver() {
+	gameOver = true
+	soundGameOver.Play()
+	music.SetMood(musicMoodGameOver)
+	levelTimeLeft = 0.0
+	overlayScore.Update(fmt.Sprintf("Your score: %d", currentGame.Score()), applicationRenderer)
+	overlaySeed.Update(fmt.Sprintf("Seed: %d", currentGame.Seed()), applicationRenderer)
+	overlayHint.Update("Press Enter to see the high scores", applicationRenderer)
+	updateStatisticsOverlay(currentGame.Stats().Summary())
+	exportStatistics(currentGame.Stats())
+	saveRecording()
+	if replayPlaying == nil && isHighScore(currentGame.Score()) {
+		startNameEntry()
 	}
 }
 
 func handleNextLevel(level int) {
 	text := fmt.Sprintf("Level %d", level)
+	if currentGame.IsBossLevel() {
+		text = fmt.Sprintf("Level %d: Boss", level)
+	}
 	overlayLevel.Update(text, applicationRenderer)
 	levelTimeLeft = levelTimeToShow
+	if level > 1 {
+		soundLevelUp.Play()
+		updateMusicIntensity()
+	}
 }
 
 func init() {
 	runtime.LockOSThread()
 }
 
+var (
+	// Flags that change a tuning setting directly, applied again after
+	// the configuration file so that they win over it.
+	gameConfigFlags []string = []string{"mistype-breaks-combo", "mistype-lockout", "mistype-damage"}
+	flagsGiven      map[string]string
+)
+
+func parseFlags() {
+	initTuningSettings()
+	defaultTuning = saveTuning()
+	flag.StringVar(&configPath, "config", "", "load tuning settings from the JSON `file`")
+	flag.Var(&configOverrides, "set", "override a tuning setting, as `name=value` with a JSON value, e.g. game.playerStartHealth=200")
+	flag.BoolVar(&configPrint, "print-config", false, "print the tuning settings as JSON and exit")
+	flag.Int64Var(&gameSeed, "seed", 0, "seed for the asteroid waves, random if not set")
+	flag.StringVar(&replayRecordPath, "record", "", "save a replay of each game to `file`")
+	flag.StringVar(&replayPlayPath, "replay", "", "play back the replay in `file`")
+	flag.StringVar(&statisticsExportPath, "stats", "", "export typing statistics of each game to `file`, as CSV if it ends in .csv and JSON otherwise")
+	flag.BoolVar(&gameConfig.MistypeBreaksCombo, "mistype-breaks-combo", gameConfig.MistypeBreaksCombo, "reset the combo when a wrong key is pressed")
+	flag.Var(float32Value{&gameConfig.MistypeLockout}, "mistype-lockout", "ignore the keyboard for `ms` milliseconds after a wrong key")
+	flag.IntVar(&gameConfig.MistypeDamage, "mistype-damage", gameConfig.MistypeDamage, "damage Earth by `n` percent for each wrong key")
+	flag.IntVar(&frameRateLimit, "fps", 0, "limit the frame rate to `n` frames per second, 0 for no limit")
+	flag.BoolVar(&verticalSync, "vsync", false, "synchronize the frame rate with the display")
+	flag.StringVar(&musicDirectory, "music", musicDirectory, "play the music and playlist.json in `directory`")
+	flag.BoolVar(&windowed, "windowed", false, "start in a window instead of fullscreen")
+	flag.Var(resolutionValue{&windowWidth, &windowHeight}, "resolution", "size of the window as `WIDTHxHEIGHT` when started with --windowed")
+	flag.Var(float32Value{&screenScale}, "scale", "scale the game by `factor`, from 0.5 to 2")
+	flag.Var(&fallbackFonts, "fallback-font", "draw characters the game's font lacks with the font in `file`, tried before the system fonts")
+	flag.StringVar(&resourceDirectory, "resources", "", "use the files in `directory` in place of the built-in resources with the same name")
+	flag.Parse()
+	resources = NewResourceManager(resourceDirectory)
+	flagsGiven = make(map[string]string)
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			gameSeedSet = true
+		}
+		flagsGiven[f.Name] = f.Value.String()
+	})
+
+	initKeyboardLayouts()
+	initSettings()
+	err := applyDifficulty()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(2)
+	}
+	if screenScale < screenScaleMin || screenScale > screenScaleMax {
+		fmt.Fprintf(os.Stderr, "Invalid scale %v, must be between %v and %v\n", screenScale, screenScaleMin, screenScaleMax)
+		os.Exit(2)
+	}
+	if configPrint {
+		err = printConfiguration()
+		if err != nil {
+			panic(err)
+		}
+		os.Exit(0)
+	}
+}
+
+func flagGiven(name string) bool {
+	_, ok := flagsGiven[name]
+	return ok
+}
+
+// applyConfiguration applies the configuration file, the --set overrides
+// and the flags that change tuning settings, in that order, and checks
+// the result.
+func applyConfiguration() error {
+	err := loadConfiguration()
+	if err != nil {
+		return err
+	}
+	for _, name := range gameConfigFlags {
+		value, ok := flagsGiven[name]
+		if ok {
+			flag.Set(name, value)
+		}
+	}
+	return validateConfiguration()
+}
+
+type float32Value struct {
+	value *float32
+}
+
+func (f float32Value) String() string {
+	if f.value == nil {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(*f.value), 'g', -1, 32)
+}
+
+func (f float32Value) Set(s string) error {
+	value, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		return err
+	}
+	*f.value = float32(value)
+	return nil
+}
+
+func newGameSeed() int64 {
+	if gameSeedSet {
+		return gameSeed
+	}
+	return time.Now().UTC().UnixNano()
+}
+
 func main() {
+	parseFlags()
 	rand.Seed(time.Now().UTC().UnixNano())
 	sdl.Init(sdl.INIT_EVERYTHING)
 	img.Init(img.INIT_PNG)
 	ttf.Init()
 	mix.Init(mix.INIT_OGG)
 
-	var windowFlags uint32 = sdl.WINDOW_FULLSCREEN_DESKTOP
+	createWindow()
+	defer applicationWindow.Destroy()
+	sdl.StartTextInput()
 
-	window, err := sdl.CreateWindow("Astrotyper", sdl.WINDOWPOS_UNDEFINED,
-		sdl.WINDOWPOS_UNDEFINED, 0, 0, windowFlags)
-	if err != nil {
-		panic(err)
+	var rendererFlags uint32 = sdl.RENDERER_ACCELERATED
+	if verticalSync {
+		rendererFlags |= sdl.RENDERER_PRESENTVSYNC
 	}
-	defer window.Destroy()
 
-	applicationRenderer, err = sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED)
+	var err error
+	applicationRenderer, err = sdl.CreateRenderer(applicationWindow, -1, rendererFlags)
 	if err != nil {
 		panic(err)
 	}
 	defer applicationRenderer.Destroy()
 
-	ScreenWidth, ScreenHeight, err = applicationRenderer.GetOutputSize()
+	resizeScreen()
+	err = newGameConfig().Validate()
 	if err != nil {
 		panic(err)
 	}
 
-	err = mix.OpenAudio(mix.DEFAULT_FREQUENCY, mix.DEFAULT_FORMAT, mix.DEFAULT_CHANNELS, mix.DEFAULT_CHUNKSIZE)
+	wordPacks, err = simulation.LoadWordPacks(resources, wordPackDirectory)
 	if err != nil {
-		panic(err)
+		exitWithError(err)
 	}
+	selectSavedWordPack()
+	initHighScores()
 
-	music, err := mix.LoadMUS("resources/music/ObservingTheStar.ogg")
+	err = mix.OpenAudio(mix.DEFAULT_FREQUENCY, mix.DEFAULT_FORMAT, mix.DEFAULT_CHANNELS, mix.DEFAULT_CHUNKSIZE)
 	if err != nil {
 		panic(err)
 	}
 
-	music.Play(-1)
-
-	background1 := NewBackground(100, 1, 1, 0.2)
-	background2 := NewBackground(10, 1, 1, 0.3)
+	initSounds()
+	initMusic()
+	applyVolume()
+	music.SetMood(musicMoodMenu)
 
 	createMainMenu()
 
@@ -249,16 +457,26 @@ func main() {
 	var deltaTime float32
 
 	mainMenu = true
+	if replayPlayPath != "" {
+		startReplay(replayPlayPath)
+	}
 
 	applicationRunning = true
 	for applicationRunning {
 		currentTime = sdl.GetTicks()
 		deltaTime = float32(currentTime - lastTime)
 		lastTime = currentTime
+		if deltaTime > maxFrameTime {
+			deltaTime = maxFrameTime
+		}
 
 		handleEvents()
+		music.Update()
+		if replayPlaying != nil && !mainMenu {
+			playReplayEvents()
+		}
 
-		if !gameOver {
+		if !gameOver && !gamePaused {
 			background1.Update(deltaTime)
 			background2.Update(deltaTime)
 		}
@@ -269,36 +487,71 @@ func main() {
 
 		if !mainMenu {
 			if !gamePaused && !gameOver {
+				simulationAccumulator += deltaTime
+				for simulationAccumulator >= simulationStepTime && !mainMenu && !gameOver {
+					if !stepSimulation() {
+						break
+					}
+					updateAsteroidSprites(simulationStepTime)
+					simulationAccumulator -= simulationStepTime
+				}
 				currentPlayer.Update(deltaTime)
-				currentGame.Update(deltaTime)
+				updateExplosions(deltaTime)
+				updateKeyboard(deltaTime)
 			}
 		}
 
+		if !mainMenu && currentWord != currentGame.Input() {
+			currentWord = currentGame.Input()
+			updateCurrentWordTexture()
+		}
+		if !mainMenu {
+			updateFingerHint()
+		}
+
 		applicationRenderer.SetDrawColor(0, 0, 0, 255)
 		applicationRenderer.Clear()
 
 		background1.Draw(applicationRenderer)
 		background2.Draw(applicationRenderer)
 
-		if !mainMenu {
+		if highScoresShown {
+			drawHighScores()
+		} else if !mainMenu {
 			currentPlayer.Draw(applicationRenderer)
-			currentGame.Draw(applicationRenderer)
+			drawGame(applicationRenderer, simulationAccumulator/simulationStepTime)
 
-			drawLevel(deltaTime)
+			if gamePaused {
+				drawLevel(0.0)
+			} else {
+				drawLevel(deltaTime)
+			}
 			drawGameOver()
 			drawHUD()
+			drawKeyboard()
 			drawCurrentWord()
+			drawFingerHint()
+			if gamePaused {
+				drawPauseMenu()
+			}
 		} else {
 			drawMainMenu()
 		}
 
 		applicationRenderer.Present()
+		limitFrameRate(currentTime)
+	}
+
+	if !mainMenu && !gameOver {
+		saveRecording()
 	}
 
 	//levelFont.Close()
 
 	music.Free()
+	freeSounds()
 	mix.CloseAudio()
+	resources.Free()
 
 	mix.Quit()
 	ttf.Quit()
@@ -306,14 +559,24 @@ func main() {
 	sdl.Quit()
 }
 
-func startGame() {
+func limitFrameRate(frameStart uint32) {
+	if frameRateLimit <= 0 {
+		return
+	}
+	frameTime := uint32(1000 / frameRateLimit)
+	elapsed := sdl.GetTicks() - frameStart
+	if elapsed < frameTime {
+		sdl.Delay(frameTime - elapsed)
+	}
+}
+
+func startGame(seed int64) {
 	if asteroidFont == nil {
 		asteroidFont = openFont(fontPath, asteroidFontSize)
 	}
 	if currentWordFont == nil {
 		currentWordFont = openFont(fontPath, currentWordFontSize)
 	}
-	updateCurrentWordTexture()
 
 	if hudEarth == nil {
 		hudEarth = NewText(fontPath, hudFontSize)
@@ -323,11 +586,13 @@ func startGame() {
 		hudScore = NewText(fontPath, hudFontSize)
 	}
 	hudScore.Update("Score: 0", applicationRenderer)
+	if hudCombo == nil {
+		hudCombo = NewText(fontPath, hudFontSize)
+	}
 
 	if overlayLevel == nil {
 		overlayLevel = NewText(fontPath, levelFontSize)
 	}
-	handleNextLevel(1)
 
 	if overlayGameOver == nil {
 		overlayGameOver = NewText(fontPath, levelFontSize)
@@ -336,25 +601,56 @@ func startGame() {
 	if overlayScore == nil {
 		overlayScore = NewText(fontPath, levelFontSize)
 	}
+	if overlaySeed == nil {
+		overlaySeed = NewText(fontPath, hudFontSize)
+	}
+	if overlayHint == nil {
+		overlayHint = NewText(fontPath, hudFontSize)
+	}
 
 	if currentPlayer == nil {
-		currentPlayer = NewPlayer(applicationRenderer)
+		var err error
+		currentPlayer, err = NewPlayer(applicationRenderer)
+		if err != nil {
+			exitWithError(err)
+		}
 	}
-	currentPlayer.Reset()
-	if currentGame == nil {
-		currentGame = NewGame()
+	setPlayfieldWidth(0)
+	config := newGameConfig()
+	if replayPlaying != nil {
+		config = replayPlaying.Config
 	}
-	currentGame.Start(handleAsteroidNotDestroyed, handleNextLevel)
+	currentGame = simulation.NewGame(config)
+	setPlayfieldWidth(int32(config.Width))
+	resetGameSprites()
+	startRecording(seed, config)
+	simulationAccumulator = 0.0
+	currentGame.Start(seed, currentWordPack(), simulation.Callbacks{
+		AsteroidNotDestroyed: handleAsteroidNotDestroyed,
+		AsteroidDestroyed:    handleAsteroidDestroyed,
+		NextLevel:            handleNextLevel,
+		GameOver:             handleGameOver,
+		Mistype:              handleMistype,
+		TargetLocked:         handleTargetLocked,
+		AsteroidCracked:      handleAsteroidCracked,
+	})
+	setKeyboardLayout(currentGame.Layout())
+	handleNextLevel(1)
+	music.SetMood(musicMoodGame)
+	updateHUDCombo()
+	currentWord = ""
+	currentComposition = ""
+	updateCurrentWordTexture()
+	resetFingerHint()
 
 	gameOver = false
 	gamePaused = false
-	playerScore = 0
 }
 
 func openFont(path string, size int) *ttf.Font {
-	font, err := ttf.OpenFont(fontPath, size)
+	font, err := resources.Font(path, size)
 	if err != nil {
-		panic(err)
+		exitWithError(err)
 	}
 	return font
 }
@@ -362,35 +658,35 @@ func openFont(path string, size int) *ttf.Font {
 func createMainMenu() {
 	if menuLogoTexture == nil {
 		var err error
-		menuLogoTexture, err = img.LoadTexture(applicationRenderer, "resources/menu/logo.png")
+		menuLogoTexture, err = resources.Texture(applicationRenderer, menuLogoTexturePath)
 		if err != nil {
-			panic(err)
+			exitWithError(err)
 		}
 		_, _, menuLogoTextureWidth, menuLogoTextureHeight, err = menuLogoTexture.Query()
 		if err != nil {
 			panic(err)
 		}
 	}
-	if menuItemStart == nil {
-		menuItemStart = NewText(fontPath, menuItemFontSize)
-	}
-	menuItemStartText := "New Game"
-	if menuItemSelected == 0 {
-		menuItemStartText = "* New Game *"
-	}
-	menuItemStart.Update(menuItemStartText, applicationRenderer)
-	if menuItemQuit == nil {
-		menuItemQuit = NewText(fontPath, menuItemFontSize)
-	}
-	menuItemQuitText := "Quit"
-	if menuItemSelected == 1 {
-		menuItemQuitText = "* Quit *"
+	createSettingsMenu()
+	createPauseMenu()
+	mainMenuRoot = NewMenu("",
+		NewMenuButton("New Game", func() {
+			startGame(newGameSeed())
+			mainMenu = false
+		}),
+		NewMenuButton("High Scores", showHighScores),
+		NewSubmenuItem("Settings", settingsMenu),
+		NewMenuButton("Quit", func() {
+			applicationRunning = false
+		}),
+	)
+	mainMenuRoot.Close = func() {
+		applicationRunning = false
 	}
-	menuItemQuit.Update(menuItemQuitText, applicationRenderer)
+	openMenu(mainMenuRoot)
 	if menuLogoJetBeam == nil {
-		menuLogoJetBeam = NewJetBeamParticleEffect(
-			float32((ScreenWidth/2)-(menuLogoTextureWidth/2)+menuLogoJetBeamOffsetX),
-			float32(menuLogoY()+menuLogoTextureHeight+menuLogoJetBeamOffsetY),
+		x, y := menuLogoJetBeamPosition()
+		menuLogoJetBeam = NewJetBeamParticleEffect(x, y,
 			menuLogoJetBeamWidth,
 			menuLogoJetBeamHeight,
 			menuLogoJetBeamYellowParticles,
@@ -398,6 +694,18 @@ func createMainMenu() {
 	}
 }
 
+func showMainMenu() {
+	music.SetMood(musicMoodMenu)
+	mainMenu = true
+	gameOver = false
+	openMenu(mainMenuRoot)
+}
+
+func menuLogoJetBeamPosition() (float32, float32) {
+	return float32((ScreenWidth / 2) - (menuLogoTextureWidth / 2) + menuLogoJetBeamOffsetX),
+		float32(menuLogoY() + menuLogoTextureHeight + menuLogoJetBeamOffsetY)
+}
+
 func drawMainMenu() {
 	menuLogoJetBeam.Draw(applicationRenderer)
 	applicationRenderer.Copy(menuLogoTexture, nil, &sdl.Rect{
@@ -406,16 +714,15 @@ func drawMainMenu() {
 		W: menuLogoTextureWidth,
 		H: menuLogoTextureHeight,
 	})
-	menuItemStart.Draw(applicationRenderer,
-		(ScreenWidth/2)-(menuItemStart.Width()/2),
-		(ScreenHeight/2)-(menuItemStart.Height()))
-	menuItemQuit.Draw(applicationRenderer,
-		(ScreenWidth/2)-(menuItemQuit.Width()/2),
-		(ScreenHeight/2)+(menuItemStart.Height()))
+	if activeMenu == mainMenuRoot {
+		activeMenu.Draw((ScreenHeight / 2) - mainMenuRoot.ItemHeight())
+	} else {
+		activeMenu.Draw(menuLogoY() + menuLogoTextureHeight)
+	}
 }
 
 func menuLogoY() int32 {
-	return (((ScreenHeight / 2) - menuItemStart.Height()) / 2) -
+	return (((ScreenHeight / 2) - mainMenuRoot.ItemHeight()) / 2) -
 		(menuLogoTextureHeight / 2)
 }
 
@@ -430,16 +737,31 @@ func drawLevel(deltaTime float32) {
 
 func drawGameOver() {
 	if gameOver {
+		y := (ScreenHeight / 3) - (overlayGameOver.Height() / 2)
 		overlayGameOver.Draw(applicationRenderer,
-			(ScreenWidth/2)-(overlayGameOver.Width()/2),
-			(ScreenHeight/3)-(overlayGameOver.Height()/2))
+			(ScreenWidth/2)-(overlayGameOver.Width()/2), y)
+		y += overlayGameOver.Height() + 64
 		overlayScore.Draw(applicationRenderer,
-			(ScreenWidth/2)-(overlayScore.Width()/2),
-			(ScreenHeight/3)-(overlayScore.Height()/2)+overlayGameOver.Height()+64)
+			(ScreenWidth/2)-(overlayScore.Width()/2), y)
+		y += overlayScore.Height() + 32
+		overlaySeed.Draw(applicationRenderer,
+			(ScreenWidth/2)-(overlaySeed.Width()/2), y)
+		y += overlaySeed.Height() + 32
+		y = drawStatisticsOverlay(y)
+		y += 64
+		prompt := overlayHint
+		if nameEntry {
+			prompt = overlayName
+		}
+		prompt.Draw(applicationRenderer,
+			(ScreenWidth/2)-(prompt.Width()/2), y)
 	}
 }
 
 func drawHUD() {
+	hudCombo.Draw(applicationRenderer,
+		ScreenWidth-hudCombo.Width()-hudMarginRight,
+		ScreenHeight-hudCombo.Height()-hudMarginBottom-hudScore.Height()-hudEarth.Height())
 	hudEarth.Draw(applicationRenderer,
 		ScreenWidth-hudEarth.Width()-hudMarginRight,
 		ScreenHeight-hudEarth.Height()-hudMarginBottom-hudScore.Height())
@@ -456,7 +778,7 @@ func updateFontTexture(text string, font *ttf.Font, texture **sdl.Texture, width
 		*width = 0
 		*height = 0
 	}
-	surface, err := font.RenderUTF8Blended(text, color)
+	surface, err := renderText(font, text, color)
 	if err == nil {
 		w := surface.W
 		h := surface.H
@@ -471,7 +793,7 @@ func updateFontTexture(text string, font *ttf.Font, texture **sdl.Texture, width
 }
 
 func updateCurrentWordTexture() {
-	updateFontTexture(currentWord+"_",
+	updateFontTexture(currentWord+currentComposition+"_",
 		currentWordFont,
 		&currentWordTexture,
 		&currentWordTextureWidth,
@@ -483,14 +805,36 @@ func updateCurrentWordTexture() {
 			A: 255})
 }
 
-func drawCurrentWord() {
+func currentWordBackground() *sdl.Rect {
 	background := &sdl.Rect{}
-	border := &sdl.Rect{}
-
 	background.X = (ScreenWidth / 2) - (currentWordWidth / 2) - currentWordPadding
 	background.Y = ScreenHeight - currentWordHeight - currentWordPadding - currentWordMargin
 	background.W = currentWordWidth + (currentWordPadding * 2)
 	background.H = currentWordHeight + (currentWordPadding * 2)
+	return background
+}
+
+// placeTextInput tells the input method where the typed word is shown, so
+// that the candidate window of an IME opens next to it instead of over
+// the asteroids. The rectangle is in window coordinates.
+func placeTextInput() {
+	_, windowHeight := applicationWindow.GetSize()
+	if ScreenHeight <= 0 || windowHeight <= 0 {
+		return
+	}
+	scale := float32(windowHeight) / float32(ScreenHeight)
+	background := currentWordBackground()
+	sdl.SetTextInputRect(&sdl.Rect{
+		X: int32(float32(background.X) * scale),
+		Y: int32(float32(background.Y) * scale),
+		W: int32(float32(background.W) * scale),
+		H: int32(float32(background.H) * scale),
+	})
+}
+
+func drawCurrentWord() {
+	background := currentWordBackground()
+	border := &sdl.Rect{}
 
 	border.X = background.X - currentWordBorder
 	border.Y = background.Y - currentWordBorder
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	menuTitleFontSize int   = 64
+	menuItemSpacing   int32 = 16
+	menuSliderWidth   int   = 10
+
+	activeMenu *Menu
+)
+
+type MenuAction func()
+type MenuValue func() string
+type MenuChange func(direction int)
+
+// MenuItem is a single line of a menu. Buttons have an Action, submenu
+// items a Submenu, and toggles, sliders and choices a Value that is shown
+// next to the label and changed with Left and Right.
+type MenuItem struct {
+	Label   string
+	Action  MenuAction
+	Submenu *Menu
+	Value   MenuValue
+	Change  MenuChange
+}
+
+type Menu struct {
+	Title    string
+	Items    []*MenuItem
+	Close    MenuAction
+	parent   *Menu
+	selected int
+	title    *Text
+	texts    []*Text
+}
+
+func NewMenuButton(label string, action MenuAction) *MenuItem {
+	return &MenuItem{Label: label, Action: action}
+}
+
+func NewSubmenuItem(label string, submenu *Menu) *MenuItem {
+	return &MenuItem{Label: label, Submenu: submenu}
+}
+
+func NewMenuValue(label string, value MenuValue, change MenuChange) *MenuItem {
+	return &MenuItem{Label: label, Value: value, Change: change}
+}
+
+func NewMenuToggle(label string, value *bool, changed MenuAction) *MenuItem {
+	return NewMenuValue(label,
+		func() string {
+			return onOff(*value)
+		},
+		func(direction int) {
+			*value = !*value
+			if changed != nil {
+				changed()
+			}
+		})
+}
+
+func NewMenuSlider(label string, value *int, min int, max int, step int, changed MenuAction) *MenuItem {
+	return NewMenuValue(label,
+		func() string {
+			filled := (*value - min) * menuSliderWidth / (max - min)
+			return fmt.Sprintf("[%s%s] %d", strings.Repeat("|", filled),
+				strings.Repeat(".", menuSliderWidth-filled), *value)
+		},
+		func(direction int) {
+			*value += direction * step
+			if *value < min {
+				*value = min
+			}
+			if *value > max {
+				*value = max
+			}
+			if changed != nil {
+				changed()
+			}
+		})
+}
+
+func NewMenuChoice(label string, options []string, selected *int, changed MenuAction) *MenuItem {
+	return NewMenuValue(label,
+		func() string {
+			return options[*selected]
+		},
+		func(direction int) {
+			*selected = (*selected + direction + len(options)) % len(options)
+			if changed != nil {
+				changed()
+			}
+		})
+}
+
+func onOff(value bool) string {
+	if value {
+		return "On"
+	}
+	return "Off"
+}
+
+func NewMenu(title string, items ...*MenuItem) *Menu {
+	menu := &Menu{}
+	menu.Title = title
+	menu.Items = items
+	return menu
+}
+
+func (menu *Menu) Selected() *MenuItem {
+	return menu.Items[menu.selected]
+}
+
+func (menu *Menu) Update() {
+	if menu.title == nil && menu.Title != "" {
+		menu.title = NewText(fontPath, menuTitleFontSize)
+	}
+	if menu.title != nil {
+		menu.title.Update(menu.Title, applicationRenderer)
+	}
+	for len(menu.texts) < len(menu.Items) {
+		menu.texts = append(menu.texts, NewText(fontPath, menuItemFontSize))
+	}
+	for i, item := range menu.Items {
+		text := item.Label
+		if item.Value != nil {
+			text += ": " + item.Value()
+		}
+		if i == menu.selected {
+			if item.Change != nil {
+				text = "< " + text + " >"
+			} else {
+				text = "* " + text + " *"
+			}
+		}
+		menu.texts[i].Update(text, applicationRenderer)
+	}
+}
+
+func openMenu(menu *Menu) {
+	activeMenu = menu
+	activeMenu.selected = 0
+	activeMenu.Update()
+}
+
+func openSubmenu(menu *Menu) {
+	menu.parent = activeMenu
+	openMenu(menu)
+}
+
+// closeMenu goes back to the parent menu, or calls the Close action of a
+// menu that has no parent, such as resuming the game from the pause menu.
+func closeMenu() {
+	menu := activeMenu
+	if menu.parent == nil {
+		if menu.Close != nil {
+			menu.Close()
+		}
+		return
+	}
+	activeMenu = menu.parent
+	menu.parent = nil
+	if menu.Close != nil {
+		menu.Close()
+	}
+	activeMenu.Update()
+}
+
+func handleMenuKey(key sdl.Keycode) {
+	menu := activeMenu
+	item := menu.Selected()
+	if key == sdl.K_ESCAPE {
+		closeMenu()
+	} else if key == sdl.K_UP {
+		menu.selected = (menu.selected + len(menu.Items) - 1) % len(menu.Items)
+		menu.Update()
+	} else if key == sdl.K_DOWN {
+		menu.selected = (menu.selected + 1) % len(menu.Items)
+		menu.Update()
+	} else if key == sdl.K_LEFT {
+		if item.Change != nil {
+			item.Change(-1)
+			menu.Update()
+		}
+	} else if key == sdl.K_RIGHT {
+		if item.Change != nil {
+			item.Change(1)
+			menu.Update()
+		}
+	} else if key == sdl.K_RETURN {
+		if item.Submenu != nil {
+			openSubmenu(item.Submenu)
+		} else if item.Action != nil {
+			item.Action()
+		} else if item.Change != nil {
+			item.Change(1)
+			menu.Update()
+		}
+	}
+}
+
+func (menu *Menu) ItemHeight() int32 {
+	if len(menu.texts) == 0 {
+		return 0
+	}
+	return menu.texts[0].Height()
+}
+
+// Draw draws the menu centered on the screen starting at y and returns the
+// y below the last item.
+func (menu *Menu) Draw(y int32) int32 {
+	if menu.title != nil {
+		menu.title.Draw(applicationRenderer, (ScreenWidth/2)-(menu.title.Width()/2), y)
+		y += menu.title.Height() + 64
+	}
+	for _, text := range menu.texts[:len(menu.Items)] {
+		text.Draw(applicationRenderer, (ScreenWidth/2)-(text.Width()/2), y)
+		y += text.Height() + menuItemSpacing
+	}
+	return y
+}
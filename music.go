@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/veandco/go-sdl2/mix"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	musicDirectory    string = "resources/music"
+	musicPlaylistFile string = "playlist.json"
+	musicChannels     int    = 2
+	musicFadeTime     int    = 2000
+
+	musicIntenseLevel  int = 5
+	musicIntenseHealth int = 30
+
+	musicMoodMenu     string = "menu"
+	musicMoodGame     string = "game"
+	musicMoodIntense  string = "intense"
+	musicMoodGameOver string = "gameOver"
+
+	music *MusicManager
+)
+
+// MusicManager plays the tracks of the playlist that belongs to the current
+// mood, such as the menu or a game that is going badly. Tracks are played
+// as chunks on two reserved channels, so that one can fade out while the
+// next fades in. A chunk holds the whole decoded track, so tracks are only
+// loaded when they start and freed once they have faded out.
+type MusicManager struct {
+	files     fs.FS
+	directory string
+	playlists map[string][]string
+	tracks    map[string]*mix.Chunk
+	channels  []string
+	mood      string
+	position  int
+	current   string
+	channel   int
+	volume    int
+	muted     bool
+}
+
+// NewMusicManager reads the playlists from playlist.json in the directory
+// of files, which maps each mood to a list of files. Without a playlist
+// file every .ogg and .wav file in the directory is played in every mood.
+func NewMusicManager(files fs.FS, directory string) (*MusicManager, error) {
+	manager := &MusicManager{}
+	manager.files = files
+	manager.directory = directory
+	manager.playlists = make(map[string][]string)
+	manager.tracks = make(map[string]*mix.Chunk)
+	manager.channels = make([]string, musicChannels)
+	manager.volume = 100
+
+	playlistPath := path.Join(directory, musicPlaylistFile)
+	data, err := fs.ReadFile(files, playlistPath)
+	if err == nil {
+		err = json.Unmarshal(data, &manager.playlists)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", playlistPath, err)
+		}
+	} else if errors.Is(err, fs.ErrNotExist) {
+		entries, err := fs.ReadDir(files, directory)
+		if err != nil {
+			return nil, err
+		}
+		tracks := make([]string, 0)
+		for _, file := range entries {
+			extension := strings.ToLower(path.Ext(file.Name()))
+			if extension == ".ogg" || extension == ".wav" {
+				tracks = append(tracks, file.Name())
+			}
+		}
+		sort.Strings(tracks)
+		for _, mood := range []string{musicMoodMenu, musicMoodGame, musicMoodIntense, musicMoodGameOver} {
+			manager.playlists[mood] = tracks
+		}
+	} else {
+		return nil, err
+	}
+	if len(manager.playlists[musicMoodGame]) == 0 {
+		return nil, fmt.Errorf("%s: no music for the %q mood", directory, musicMoodGame)
+	}
+	return manager, nil
+}
+
+func (manager *MusicManager) playlist(mood string) []string {
+	tracks := manager.playlists[mood]
+	if len(tracks) == 0 {
+		tracks = manager.playlists[musicMoodGame]
+	}
+	return tracks
+}
+
+func (manager *MusicManager) track(name string) (*mix.Chunk, error) {
+	chunk, ok := manager.tracks[name]
+	if ok {
+		return chunk, nil
+	}
+	chunk, err := loadChunk(manager.files, path.Join(manager.directory, name))
+	if err != nil {
+		return nil, err
+	}
+	manager.tracks[name] = chunk
+	return chunk, nil
+}
+
+// play fades the track in on the free channel while the one that is
+// playing fades out. A playlist with a single track loops it.
+func (manager *MusicManager) play(name string, loops int) {
+	chunk, err := manager.track(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not play music: %v\n", err)
+		return
+	}
+	if manager.current != "" {
+		mix.FadeOutChannel(manager.channel, musicFadeTime)
+		manager.channel = (manager.channel + 1) % musicChannels
+	}
+	manager.current = name
+	manager.channels[manager.channel] = name
+	manager.applyVolume()
+	_, err = chunk.FadeIn(manager.channel, loops, musicFadeTime)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not play music: %v\n", err)
+		manager.current = ""
+	}
+}
+
+func (manager *MusicManager) loops() int {
+	if len(manager.playlist(manager.mood)) == 1 {
+		return -1
+	}
+	return 0
+}
+
+// SetMood switches to the playlist of the mood. When the track that is
+// playing is also in the new playlist it keeps playing.
+func (manager *MusicManager) SetMood(mood string) {
+	if mood == manager.mood {
+		return
+	}
+	manager.mood = mood
+	tracks := manager.playlist(mood)
+	for i, track := range tracks {
+		if track == manager.current {
+			manager.position = i
+			return
+		}
+	}
+	manager.position = 0
+	manager.play(tracks[0], manager.loops())
+}
+
+// playing reports whether the track is on a channel, including while it
+// fades out.
+func (manager *MusicManager) playing(name string) bool {
+	for channel, track := range manager.channels {
+		if track == name && mix.Playing(channel) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// freeTracks frees the tracks that have stopped or faded out.
+func (manager *MusicManager) freeTracks() {
+	for name, chunk := range manager.tracks {
+		if name != manager.current && !manager.playing(name) {
+			chunk.Free()
+			delete(manager.tracks, name)
+		}
+	}
+}
+
+// Update starts the next track of the playlist when the current one has
+// finished.
+func (manager *MusicManager) Update() {
+	manager.freeTracks()
+	if manager.current == "" || mix.Playing(manager.channel) != 0 {
+		return
+	}
+	tracks := manager.playlist(manager.mood)
+	manager.position = (manager.position + 1) % len(tracks)
+	manager.current = ""
+	manager.play(tracks[manager.position], manager.loops())
+}
+
+func (manager *MusicManager) applyVolume() {
+	volume := manager.volume * mix.MAX_VOLUME / 100
+	if manager.muted {
+		volume = 0
+	}
+	for channel := 0; channel < musicChannels; channel++ {
+		mix.Volume(channel, volume)
+	}
+}
+
+func (manager *MusicManager) SetVolume(volume int) {
+	manager.volume = volume
+	manager.applyVolume()
+}
+
+func (manager *MusicManager) SetMuted(muted bool) {
+	manager.muted = muted
+	manager.applyVolume()
+}
+
+func (manager *MusicManager) Free() {
+	for channel := 0; channel < musicChannels; channel++ {
+		mix.HaltChannel(channel)
+	}
+	for _, chunk := range manager.tracks {
+		chunk.Free()
+	}
+}
+
+func isMuteKey(t *sdl.KeyboardEvent) bool {
+	return t.Keysym.Sym == sdl.K_m && t.Keysym.Mod&sdl.KMOD_CTRL != 0
+}
+
+// initMusic plays the music from the resources, or from the directory given
+// with --music.
+func initMusic() {
+	var files fs.FS = resources
+	directory := musicDirectory
+	if flagGiven("music") {
+		files = os.DirFS(musicDirectory)
+		directory = "."
+	}
+	var err error
+	music, err = NewMusicManager(files, directory)
+	if err != nil {
+		exitWithError(err)
+	}
+}
+
+// updateMusicIntensity switches to the intense playlist once the level is
+// high enough or Earth is badly damaged.
+func updateMusicIntensity() {
+	if currentGame.Level() >= musicIntenseLevel || currentGame.IsBossLevel() ||
+		currentGame.Player().CurrentHealth() < musicIntenseHealth {
+		music.SetMood(musicMoodIntense)
+	} else {
+		music.SetMood(musicMoodGame)
+	}
+}
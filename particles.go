@@ -94,6 +94,13 @@ func (particle *Particle) Reset() {
 	particle.currentY = particle.originY
 }
 
+func (particle *Particle) Move(deltaX float32, deltaY float32) {
+	particle.originX += deltaX
+	particle.originY += deltaY
+	particle.currentX += deltaX
+	particle.currentY += deltaY
+}
+
 func (particle *Particle) Update(deltaTime float32) {
 	if !particle.alive {
 		return
@@ -195,6 +202,19 @@ func (jetBeam *JetBeamParticleEffect) Update(deltaTime float32) {
 	}
 }
 
+func (jetBeam *JetBeamParticleEffect) MoveTo(x float32, y float32) {
+	deltaX := x - jetBeam.originX
+	deltaY := y - jetBeam.originY
+	jetBeam.originX = x
+	jetBeam.originY = y
+	for _, particle := range jetBeam.yellowParticles {
+		particle.Move(deltaX, deltaY)
+	}
+	for _, particle := range jetBeam.orangeParticles {
+		particle.Move(deltaX, deltaY)
+	}
+}
+
 func (jetBeam *JetBeamParticleEffect) Draw(renderer *sdl.Renderer) {
 	for _, particle := range jetBeam.orangeParticles {
 		particle.Draw(renderer)
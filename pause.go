@@ -0,0 +1,73 @@
+package main
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	pauseOverlayAlpha uint8 = 160
+
+	pauseMenu *Menu
+	pauseHint *Text
+)
+
+func isPauseKey(t *sdl.KeyboardEvent) bool {
+	if t.Keysym.Sym == sdl.K_PAUSE {
+		return true
+	}
+	return t.Keysym.Sym == sdl.K_p && t.Keysym.Mod&sdl.KMOD_CTRL != 0
+}
+
+func createPauseMenu() {
+	pauseMenu = NewMenu("PAUSED",
+		NewMenuButton("Resume", resumeGame),
+		NewMenuButton("Restart", func() {
+			saveRecording()
+			startGame(newGameSeed())
+		}),
+		NewSubmenuItem("Settings", settingsMenu),
+		NewMenuButton("Quit to Menu", func() {
+			saveRecording()
+			resumeGame()
+			showMainMenu()
+		}),
+	)
+	pauseMenu.Close = resumeGame
+	pauseHint = NewText(fontPath, hudFontSize)
+	pauseHint.Update("Press Escape to resume", applicationRenderer)
+}
+
+// Replays are not paused since the recorded key presses have to reach the
+// game at exactly the step they were made at.
+func pauseGame() {
+	if mainMenu || gameOver || gamePaused || replayPlaying != nil {
+		return
+	}
+	gamePaused = true
+	openMenu(pauseMenu)
+}
+
+func resumeGame() {
+	gamePaused = false
+}
+
+func handlePauseKey(t *sdl.KeyboardEvent) {
+	if activeMenu == pauseMenu && isPauseKey(t) {
+		resumeGame()
+		return
+	}
+	handleMenuKey(t.Keysym.Sym)
+}
+
+func drawPauseMenu() {
+	applicationRenderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	applicationRenderer.SetDrawColor(0, 0, 0, pauseOverlayAlpha)
+	applicationRenderer.FillRect(&sdl.Rect{X: 0, Y: 0, W: ScreenWidth, H: ScreenHeight})
+	applicationRenderer.SetDrawBlendMode(sdl.BLENDMODE_NONE)
+
+	y := activeMenu.Draw(ScreenHeight / 4)
+	y += 64
+	if activeMenu == pauseMenu {
+		pauseHint.Draw(applicationRenderer, (ScreenWidth/2)-(pauseHint.Width()/2), y)
+	}
+}
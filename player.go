@@ -1,12 +1,10 @@
 package main
 
 import (
-	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 var (
-	playerStartHealth            int    = 100
 	playerTexturePath            string = "resources/player.png"
 	playerTextureWidth           int32  = 64
 	playerTextureHeight          int32  = 64
@@ -20,19 +18,17 @@ var (
 )
 
 type Player struct {
-	health    int
 	rectangle sdl.Rect
 	texture   *sdl.Texture
 	jetBeam   *JetBeamParticleEffect
 }
 
-func NewPlayer(renderer *sdl.Renderer) *Player {
-	texture, err := img.LoadTexture(renderer, playerTexturePath)
+func NewPlayer(renderer *sdl.Renderer) (*Player, error) {
+	texture, err := resources.Texture(renderer, playerTexturePath)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 	player := &Player{
-		playerStartHealth,
 		sdl.Rect{
 			X: (ScreenWidth / 2) - (playerTextureWidth / 2),
 			Y: ScreenHeight + playerOffsetY,
@@ -48,22 +44,15 @@ func NewPlayer(renderer *sdl.Renderer) *Player {
 			playerJetBeamYellowParticles,
 			playerJetBeamOrangeParticles),
 	}
-	return player
+	return player, nil
 }
 
-func (player *Player) Reset() {
-	player.health = playerStartHealth
-}
-
-func (player *Player) TakeDamage(damage int) {
-	player.health -= damage
-	if player.health < 0 {
-		player.health = 0
-	}
-}
-
-func (player *Player) CurrentHealth() int {
-	return player.health
+func (player *Player) Layout() {
+	player.rectangle.X = (ScreenWidth / 2) - (playerTextureWidth / 2)
+	player.rectangle.Y = ScreenHeight + playerOffsetY
+	player.jetBeam.MoveTo(
+		float32((ScreenWidth/2)-(playerTextureWidth/4)+playerJetBeamOffsetX),
+		float32(ScreenHeight+playerOffsetY+playerJetBeamOffsetY))
 }
 
 func (player *Player) Draw(renderer *sdl.Renderer) {
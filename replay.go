@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/snosscire/astrotyper/simulation"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	replayVersion    int = 1
+	replayRecordPath string
+	replayPlayPath   string
+
+	replayRecording *Replay
+	replayPlaying   *Replay
+	replayNextEvent int
+
+	gameStep int
+)
+
+type ReplayEvent struct {
+	Step   int         `json:"step"`
+	Time   float32     `json:"time"`
+	Type   uint32      `json:"type"`
+	Sym    sdl.Keycode `json:"sym"`
+	Mod    uint16      `json:"mod"`
+	Repeat uint8       `json:"repeat,omitempty"`
+	Text   string      `json:"text,omitempty"`
+}
+
+type Replay struct {
+	Version          int               `json:"version"`
+	Seed             int64             `json:"seed"`
+	WordPack         string            `json:"wordPack"`
+	WordPackChecksum string            `json:"wordPackChecksum"`
+	Config           simulation.Config `json:"config"`
+	StepTime         float32           `json:"stepTime"`
+	Steps            int               `json:"steps"`
+	Events           []ReplayEvent     `json:"events"`
+}
+
+func NewReplay(seed int64, wordPack *simulation.WordPack, config simulation.Config) *Replay {
+	replay := &Replay{}
+	replay.Version = replayVersion
+	replay.Seed = seed
+	replay.WordPack = wordPack.Name
+	replay.WordPackChecksum = wordPack.Checksum()
+	replay.Config = config
+	replay.StepTime = simulationStepTime
+	replay.Events = make([]ReplayEvent, 0)
+	return replay
+}
+
+func LoadReplay(path string) (*Replay, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	replay := &Replay{}
+	err = json.Unmarshal(data, replay)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if replay.Version != replayVersion {
+		return nil, fmt.Errorf("%s: unsupported replay version %d", path, replay.Version)
+	}
+	err = replay.Config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if replay.StepTime != simulationStepTime {
+		return nil, fmt.Errorf("%s: recorded with a simulation step of %vms, expected %vms",
+			path, replay.StepTime, simulationStepTime)
+	}
+	return replay, nil
+}
+
+func (replay *Replay) Save(path string) error {
+	data, err := json.Marshal(replay)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func (replay *Replay) RecordEvent(step int, event *sdl.KeyboardEvent) {
+	replay.Events = append(replay.Events, ReplayEvent{
+		Step:   step,
+		Time:   float32(step) * replay.StepTime,
+		Type:   event.Type,
+		Sym:    event.Keysym.Sym,
+		Mod:    event.Keysym.Mod,
+		Repeat: event.Repeat,
+	})
+}
+
+func (replay *Replay) RecordText(step int, event *sdl.TextInputEvent) {
+	replay.Events = append(replay.Events, ReplayEvent{
+		Step: step,
+		Time: float32(step) * replay.StepTime,
+		Type: event.Type,
+		Text: event.GetText(),
+	})
+}
+
+func (replay *Replay) RecordStep() {
+	replay.Steps++
+}
+
+func (event *ReplayEvent) KeyboardEvent() *sdl.KeyboardEvent {
+	return &sdl.KeyboardEvent{
+		Type:      event.Type,
+		Timestamp: uint32(event.Time),
+		Repeat:    event.Repeat,
+		Keysym: sdl.Keysym{
+			Sym: event.Sym,
+			Mod: event.Mod,
+		},
+	}
+}
+
+func startRecording(seed int64, config simulation.Config) {
+	gameStep = 0
+	if replayPlaying != nil {
+		replayRecording = nil
+		return
+	}
+	replayRecording = NewReplay(seed, currentWordPack(), config)
+}
+
+func recordKeyboardEvent(event *sdl.KeyboardEvent) {
+	if replayRecording != nil {
+		replayRecording.RecordEvent(gameStep, event)
+	}
+}
+
+func recordTextInputEvent(event *sdl.TextInputEvent) {
+	if replayRecording != nil {
+		replayRecording.RecordText(gameStep, event)
+	}
+}
+
+func saveRecording() {
+	if replayRecording == nil || replayRecordPath == "" {
+		return
+	}
+	err := replayRecording.Save(replayRecordPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not save replay: %v\n", err)
+	}
+	replayRecording = nil
+}
+
+// selectReplayWordPack picks the word pack the replay was recorded with,
+// which has to still have the same words.
+func selectReplayWordPack(path string, replay *Replay) error {
+	for i, pack := range wordPacks {
+		if pack.Name != replay.WordPack {
+			continue
+		}
+		if pack.Checksum() != replay.WordPackChecksum {
+			return fmt.Errorf("%s: word pack %q has changed since the replay was recorded", path, replay.WordPack)
+		}
+		wordPackSelected = i
+		return nil
+	}
+	return fmt.Errorf("%s: word pack %q not found", path, replay.WordPack)
+}
+
+func startReplay(path string) {
+	replay, err := LoadReplay(path)
+	if err == nil {
+		err = selectReplayWordPack(path, replay)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not play replay: %v\n", err)
+		os.Exit(2)
+	}
+	replayPlaying = replay
+	replayNextEvent = 0
+	startGame(replay.Seed)
+	mainMenu = false
+}
+
+func stopReplay() {
+	replayPlaying = nil
+	showMainMenu()
+}
+
+func playReplayEvents() {
+	for replayPlaying != nil && replayNextEvent < len(replayPlaying.Events) {
+		event := replayPlaying.Events[replayNextEvent]
+		if event.Step != gameStep {
+			return
+		}
+		replayNextEvent++
+		if event.Type == sdl.TEXTINPUT {
+			handleTextInput(event.Text)
+		} else {
+			handleKeyboardEvent(event.KeyboardEvent())
+		}
+		if mainMenu {
+			stopReplay()
+		}
+	}
+}
+
+// While a replay is playing the recorded key presses are fed to the game
+// right before the simulation step they were made at, so that the
+// simulation sees exactly the same input as when the game was recorded.
+func stepSimulation() bool {
+	if replayPlaying != nil {
+		playReplayEvents()
+		if replayPlaying == nil {
+			return false
+		}
+		if gameStep >= replayPlaying.Steps {
+			if !gameOver {
+				stopReplay()
+			}
+			return false
+		}
+	} else if replayRecording != nil {
+		replayRecording.RecordStep()
+	}
+	currentGame.Update(simulationStepTime)
+	gameStep++
+	return true
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/mix"
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+//go:embed resources
+var embeddedResources embed.FS
+
+var (
+	resourceRoot      string = "resources"
+	resourceDirectory string
+
+	resources *ResourceManager
+)
+
+// MissingResourceError is returned for an asset that is neither in the
+// override directory nor embedded in the binary.
+type MissingResourceError struct {
+	Name     string
+	Override string
+}
+
+func (err *MissingResourceError) Error() string {
+	if err.Override == "" {
+		return fmt.Sprintf("missing resource %s: it is not embedded in the game", err.Name)
+	}
+	return fmt.Sprintf("missing resource %s: it is neither in %s nor embedded in the game",
+		err.Name, err.Override)
+}
+
+func (err *MissingResourceError) Is(target error) bool {
+	return target == fs.ErrNotExist
+}
+
+type fontKey struct {
+	path string
+	size int
+}
+
+// ResourceManager finds the assets of the game by their path below
+// resources/. A file in the override directory is used in place of the
+// embedded one with the same path, so a single sound or texture can be
+// replaced without rebuilding. Since the defaults are embedded the game
+// runs no matter which directory it is started from.
+//
+// The manager is an fs.FS, so directories such as the word packs can be
+// read through it with the functions of io/fs.
+type ResourceManager struct {
+	override     string
+	fonts        map[fontKey]*ttf.Font
+	fontData     map[string][]byte
+	fontSizes    map[*ttf.Font]int
+	missingFonts map[string]bool
+}
+
+func NewResourceManager(override string) *ResourceManager {
+	manager := &ResourceManager{}
+	manager.override = override
+	manager.fonts = make(map[fontKey]*ttf.Font)
+	manager.fontData = make(map[string][]byte)
+	manager.fontSizes = make(map[*ttf.Font]int)
+	manager.missingFonts = make(map[string]bool)
+	return manager
+}
+
+// overridePath returns where the resource would be in the override
+// directory, or "" when there is no override directory.
+func (manager *ResourceManager) overridePath(name string) string {
+	if manager.override == "" {
+		return ""
+	}
+	relative := strings.TrimPrefix(strings.TrimPrefix(name, resourceRoot), "/")
+	return filepath.Join(manager.override, filepath.FromSlash(relative))
+}
+
+// invalidPath rejects names that are not valid fs paths, such as those
+// with .. elements, so that they cannot reach outside the override
+// directory.
+func invalidPath(op, name string) error {
+	if fs.ValidPath(name) {
+		return nil
+	}
+	return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+}
+
+func (manager *ResourceManager) missing(name string) error {
+	return &MissingResourceError{Name: name, Override: manager.overridePath(name)}
+}
+
+func (manager *ResourceManager) Open(name string) (fs.File, error) {
+	err := invalidPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+	override := manager.overridePath(name)
+	if override != "" {
+		file, err := os.Open(override)
+		if err == nil {
+			return file, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	file, err := embeddedResources.Open(name)
+	if err != nil {
+		return nil, manager.missing(name)
+	}
+	return file, nil
+}
+
+func (manager *ResourceManager) ReadFile(name string) ([]byte, error) {
+	err := invalidPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+	override := manager.overridePath(name)
+	if override != "" {
+		data, err := os.ReadFile(override)
+		if err == nil {
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	data, err := embeddedResources.ReadFile(name)
+	if err != nil {
+		return nil, manager.missing(name)
+	}
+	return data, nil
+}
+
+// ReadDir lists the embedded files of the directory together with those
+// added in the override directory.
+func (manager *ResourceManager) ReadDir(name string) ([]fs.DirEntry, error) {
+	err := invalidPath("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]fs.DirEntry)
+	entries, err := embeddedResources.ReadDir(name)
+	if err == nil {
+		for _, entry := range entries {
+			found[entry.Name()] = entry
+		}
+	}
+	override := manager.overridePath(name)
+	if override != "" {
+		entries, err := os.ReadDir(override)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			found[entry.Name()] = entry
+		}
+	}
+	if len(found) == 0 {
+		return nil, manager.missing(name)
+	}
+	merged := make([]fs.DirEntry, 0, len(found))
+	for _, entry := range found {
+		merged = append(merged, entry)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Name() < merged[j].Name()
+	})
+	return merged, nil
+}
+
+// loadResource hands a file to an SDL loader. The data is kept alive until
+// the loader is done with it.
+func loadResource(files fs.FS, name string, loader func(rw *sdl.RWops) error) error {
+	data, err := fs.ReadFile(files, name)
+	if err != nil {
+		return err
+	}
+	rw, err := sdl.RWFromMem(data)
+	if err == nil {
+		err = loader(rw)
+	}
+	runtime.KeepAlive(data)
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	return nil
+}
+
+// Font opens the font once for each size and keeps it open. The font data
+// has to stay in memory for as long as any of its sizes is open.
+func (manager *ResourceManager) Font(name string, size int) (*ttf.Font, error) {
+	key := fontKey{name, size}
+	font, ok := manager.fonts[key]
+	if ok {
+		return font, nil
+	}
+	data, ok := manager.fontData[name]
+	if !ok {
+		var err error
+		data, err = manager.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		manager.fontData[name] = data
+	}
+	rw, err := sdl.RWFromMem(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	font, err = ttf.OpenFontRW(rw, 1, size)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	manager.fonts[key] = font
+	manager.fontSizes[font] = size
+	return font, nil
+}
+
+func (manager *ResourceManager) Texture(renderer *sdl.Renderer, name string) (*sdl.Texture, error) {
+	var texture *sdl.Texture
+	err := loadResource(manager, name, func(rw *sdl.RWops) (err error) {
+		texture, err = img.LoadTextureRW(renderer, rw, true)
+		return err
+	})
+	return texture, err
+}
+
+func (manager *ResourceManager) Surface(name string) (*sdl.Surface, error) {
+	var surface *sdl.Surface
+	err := loadResource(manager, name, func(rw *sdl.RWops) (err error) {
+		surface, err = img.LoadRW(rw, true)
+		return err
+	})
+	return surface, err
+}
+
+func loadChunk(files fs.FS, name string) (*mix.Chunk, error) {
+	var chunk *mix.Chunk
+	err := loadResource(files, name, func(rw *sdl.RWops) (err error) {
+		chunk, err = mix.LoadWAVRW(rw, true)
+		return err
+	})
+	return chunk, err
+}
+
+func (manager *ResourceManager) Chunk(name string) (*mix.Chunk, error) {
+	return loadChunk(manager, name)
+}
+
+func (manager *ResourceManager) Free() {
+	for key, font := range manager.fonts {
+		font.Close()
+		delete(manager.fonts, key)
+		delete(manager.fontSizes, font)
+	}
+}
+
+// exitWithError reports an error the game cannot continue after, such as
+// a missing asset, both on the terminal and in a message box for players
+// who did not start the game from one.
+func exitWithError(err error) {
+	fmt.Fprintf(os.Stderr, "astrotyper: %v\n", err)
+	sdl.ShowSimpleMessageBox(sdl.MESSAGEBOX_ERROR, "Astrotyper", err.Error(), applicationWindow)
+	os.Exit(1)
+}
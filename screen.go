@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	screenBaseHeight    int32   = 1080
+	screenMinimumAspect float32 = 4.0 / 3.0
+	screenScale         float32 = 1.0
+	screenScaleMin      float32 = 0.5
+	screenScaleMax      float32 = 2.0
+
+	windowed     bool
+	windowWidth  int32 = 1280
+	windowHeight int32 = 720
+
+	// windowScreenWidth is the width of the logical screen that fits the
+	// window, and playfieldWidth that of the game in progress.
+	windowScreenWidth int32
+	playfieldWidth    int32
+
+	applicationWindow *sdl.Window
+
+	background1 *Background
+	background2 *Background
+)
+
+type resolutionValue struct {
+	width  *int32
+	height *int32
+}
+
+func (r resolutionValue) String() string {
+	if r.width == nil || r.height == nil {
+		return ""
+	}
+	return fmt.Sprintf("%dx%d", *r.width, *r.height)
+}
+
+func (r resolutionValue) Set(s string) error {
+	parts := strings.SplitN(s, "x", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected WIDTHxHEIGHT, got %q", s)
+	}
+	width, err := strconv.ParseInt(parts[0], 10, 32)
+	if err != nil {
+		return err
+	}
+	height, err := strconv.ParseInt(parts[1], 10, 32)
+	if err != nil {
+		return err
+	}
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("resolution must be positive, got %q", s)
+	}
+	*r.width = int32(width)
+	*r.height = int32(height)
+	return nil
+}
+
+func createWindow() {
+	var windowFlags uint32 = sdl.WINDOW_RESIZABLE
+	width, height := windowWidth, windowHeight
+	if !windowed {
+		windowFlags |= sdl.WINDOW_FULLSCREEN_DESKTOP
+		width, height = 0, 0
+	}
+	var err error
+	applicationWindow, err = sdl.CreateWindow("Astrotyper", sdl.WINDOWPOS_CENTERED,
+		sdl.WINDOWPOS_CENTERED, width, height, windowFlags)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func isFullscreen() bool {
+	return applicationWindow.GetFlags()&sdl.WINDOW_FULLSCREEN_DESKTOP == sdl.WINDOW_FULLSCREEN_DESKTOP
+}
+
+func toggleFullscreen() {
+	var flags uint32 = sdl.WINDOW_FULLSCREEN_DESKTOP
+	if isFullscreen() {
+		flags = 0
+	}
+	err := applicationWindow.SetFullscreen(flags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not toggle fullscreen: %v\n", err)
+		return
+	}
+	settings.Fullscreen = flags != 0
+	fullscreenOverride = false
+	saveSettings()
+}
+
+func isFullscreenKey(t *sdl.KeyboardEvent) bool {
+	if t.Keysym.Sym == sdl.K_F11 {
+		return true
+	}
+	return t.Keysym.Sym == sdl.K_RETURN && t.Keysym.Mod&sdl.KMOD_ALT != 0
+}
+
+// The game is laid out on a logical screen that is always screenBaseHeight
+// divided by the scale high, and as wide as the window's aspect ratio
+// allows. The renderer scales it to the window, so only the width changes
+// when the window is resized. While a game is in progress the screen is
+// never narrower than the playfield it was started with, so a narrower
+// window letterboxes it.
+func resizeScreen() {
+	outputWidth, outputHeight, err := applicationRenderer.GetOutputSize()
+	if err != nil {
+		panic(err)
+	}
+	if outputWidth <= 0 || outputHeight <= 0 {
+		return
+	}
+	ScreenHeight = int32(float32(screenBaseHeight) / screenScale)
+	windowScreenWidth = int32(int64(ScreenHeight) * int64(outputWidth) / int64(outputHeight))
+	minimumWidth := int32(float32(ScreenHeight) * screenMinimumAspect)
+	if windowScreenWidth < minimumWidth {
+		windowScreenWidth = minimumWidth
+	}
+	ScreenWidth = windowScreenWidth
+	if ScreenWidth < playfieldWidth {
+		ScreenWidth = playfieldWidth
+	}
+	err = applicationRenderer.SetLogicalSize(ScreenWidth, ScreenHeight)
+	if err != nil {
+		panic(err)
+	}
+	layoutScreen()
+}
+
+// setPlayfieldWidth keeps the logical screen at least as wide as the
+// playfield of the game in progress, 0 for none.
+func setPlayfieldWidth(width int32) {
+	playfieldWidth = width
+	screenWidth := windowScreenWidth
+	if screenWidth < playfieldWidth {
+		screenWidth = playfieldWidth
+	}
+	if screenWidth != ScreenWidth {
+		resizeScreen()
+	}
+}
+
+func layoutScreen() {
+	background1 = NewBackground(100, 1, 1, 0.2)
+	background2 = NewBackground(10, 1, 1, 0.3)
+	if currentPlayer != nil {
+		currentPlayer.Layout()
+	}
+	if menuLogoJetBeam != nil {
+		menuLogoJetBeam.MoveTo(menuLogoJetBeamPosition())
+	}
+	placeTextInput()
+}
+
+func handleWindowEvent(t *sdl.WindowEvent) {
+	if t.Event == sdl.WINDOWEVENT_FOCUS_LOST {
+		pauseGame()
+	} else if t.Event == sdl.WINDOWEVENT_SIZE_CHANGED {
+		resizeScreen()
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/snosscire/astrotyper/simulation"
+)
+
+var (
+	settingsFileName string = "settings.json"
+
+	difficultyPresets []DifficultyPreset = []DifficultyPreset{
+		{"Easy", "resources/config/easy.json"},
+		{"Normal", ""},
+		{"Hard", "resources/config/hard.json"},
+	}
+	frameRateChoices []int = []int{0, 30, 60, 120, 144}
+
+	difficultySelected     int
+	keyboardLayoutSelected int
+
+	settings     Settings
+	settingsMenu *Menu
+
+	// The options --windowed and --fps only apply to the run they are given
+	// for, so until the choice is changed in the game the loaded one is saved.
+	loadedSettings     Settings
+	fullscreenOverride bool
+	frameRateOverride  bool
+)
+
+type DifficultyPreset struct {
+	Name string
+	Path string
+}
+
+// Settings are the choices made in the settings menu. They are kept in
+// $XDG_CONFIG_HOME/astrotyper/settings.json between runs.
+type Settings struct {
+	MusicVolume      int    `json:"musicVolume"`
+	MusicMuted       bool   `json:"musicMuted"`
+	SoundVolume      int    `json:"soundVolume"`
+	Fullscreen       bool   `json:"fullscreen"`
+	Difficulty       string `json:"difficulty"`
+	WordPack         string `json:"wordPack"`
+	KeyboardLayout   string `json:"keyboardLayout"`
+	FingerHints      bool   `json:"fingerHints"`
+	OnScreenKeyboard bool   `json:"onScreenKeyboard"`
+	FrameRateLimit   int    `json:"frameRateLimit"`
+}
+
+func defaultSettings() Settings {
+	return Settings{
+		MusicVolume:      100,
+		MusicMuted:       false,
+		SoundVolume:      100,
+		Fullscreen:       true,
+		Difficulty:       "Normal",
+		WordPack:         wordPackDefaultName,
+		KeyboardLayout:   simulation.DefaultKeyboardLayout,
+		FingerHints:      false,
+		OnScreenKeyboard: false,
+		FrameRateLimit:   0,
+	}
+}
+
+func configDirectory() (string, error) {
+	directory := os.Getenv("XDG_CONFIG_HOME")
+	if directory == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		directory = filepath.Join(home, ".config")
+	}
+	return filepath.Join(directory, "astrotyper"), nil
+}
+
+func settingsPath() (string, error) {
+	directory, err := configDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(directory, settingsFileName), nil
+}
+
+func loadSettings() (Settings, error) {
+	loaded := defaultSettings()
+	path, err := settingsPath()
+	if err != nil {
+		return loaded, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return loaded, nil
+	}
+	if err != nil {
+		return loaded, err
+	}
+	err = json.Unmarshal(data, &loaded)
+	if err != nil {
+		return defaultSettings(), fmt.Errorf("%s: %v", path, err)
+	}
+	return loaded, nil
+}
+
+func saveSettings() {
+	path, err := settingsPath()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	saved := settings
+	if fullscreenOverride {
+		saved.Fullscreen = loadedSettings.Fullscreen
+	}
+	if frameRateOverride {
+		saved.FrameRateLimit = loadedSettings.FrameRateLimit
+	}
+	var data []byte
+	if err == nil {
+		data, err = json.MarshalIndent(saved, "", "  ")
+	}
+	if err == nil {
+		err = ioutil.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not save settings: %v\n", err)
+	}
+}
+
+// initSettings loads the saved settings before the window is created.
+// Command-line options win over them for this run without being saved.
+func initSettings() {
+	var err error
+	settings, err = loadSettings()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load settings: %v\n", err)
+	}
+	loadedSettings = settings
+	fullscreenOverride = flagGiven("windowed")
+	if fullscreenOverride {
+		settings.Fullscreen = !windowed
+	}
+	windowed = !settings.Fullscreen
+	frameRateOverride = flagGiven("fps")
+	if frameRateOverride {
+		settings.FrameRateLimit = frameRateLimit
+	}
+	frameRateLimit = settings.FrameRateLimit
+
+	difficultySelected = 0
+	for i, preset := range difficultyPresets {
+		if preset.Name == settings.Difficulty {
+			difficultySelected = i
+		}
+	}
+	keyboardLayoutSelected = 0
+	for i, layout := range simulation.KeyboardLayouts {
+		if layout.Name == settings.KeyboardLayout {
+			keyboardLayoutSelected = i
+		}
+	}
+	settings.Difficulty = difficultyPresets[difficultySelected].Name
+	settings.KeyboardLayout = simulation.KeyboardLayouts[keyboardLayoutSelected].Name
+}
+
+// applyDifficulty loads the tuning settings again with the chosen preset
+// underneath the configuration file and command-line options.
+func applyDifficulty() error {
+	restoreTuning(defaultTuning)
+	path := difficultyPresets[difficultySelected].Path
+	if path != "" {
+		data, err := resources.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		err = loadConfigData(path, data)
+		if err != nil {
+			return err
+		}
+	}
+	err := applyConfiguration()
+	gameConfig.KeyboardLayout = settings.KeyboardLayout
+	return err
+}
+
+func selectSavedWordPack() {
+	for i, pack := range wordPacks {
+		if pack.Name == settings.WordPack {
+			wordPackSelected = i
+			return
+		}
+	}
+	selectDefaultWordPack()
+}
+
+func applyVolume() {
+	music.SetVolume(settings.MusicVolume)
+	music.SetMuted(settings.MusicMuted)
+	setSoundVolume(settings.SoundVolume)
+}
+
+func toggleMusicMuted() {
+	settings.MusicMuted = !settings.MusicMuted
+	music.SetMuted(settings.MusicMuted)
+	saveSettings()
+}
+
+func frameRateChoiceNames() []string {
+	names := make([]string, len(frameRateChoices))
+	for i, choice := range frameRateChoices {
+		names[i] = "Off"
+		if choice > 0 {
+			names[i] = fmt.Sprintf("%d fps", choice)
+		}
+	}
+	return names
+}
+
+func createSettingsMenu() {
+	difficulties := make([]string, len(difficultyPresets))
+	for i, preset := range difficultyPresets {
+		difficulties[i] = preset.Name
+	}
+	layouts := make([]string, len(simulation.KeyboardLayouts))
+	for i, layout := range simulation.KeyboardLayouts {
+		layouts[i] = layout.Name
+	}
+	packs := make([]string, len(wordPacks))
+	for i, pack := range wordPacks {
+		packs[i] = pack.Description()
+	}
+	frameRateSelected := 0
+	for i, choice := range frameRateChoices {
+		if choice == frameRateLimit {
+			frameRateSelected = i
+		}
+	}
+
+	settingsMenu = NewMenu("Settings",
+		NewMenuSlider("Music volume", &settings.MusicVolume, 0, 100, 10, applyVolume),
+		NewMenuToggle("Mute music", &settings.MusicMuted, applyVolume),
+		NewMenuSlider("Sound volume", &settings.SoundVolume, 0, 100, 10, applyVolume),
+		NewMenuToggle("Fullscreen", &settings.Fullscreen, func() {
+			if settings.Fullscreen != isFullscreen() {
+				toggleFullscreen()
+			}
+		}),
+		NewMenuChoice("Difficulty", difficulties, &difficultySelected, func() {
+			previous := settings.Difficulty
+			settings.Difficulty = difficultyPresets[difficultySelected].Name
+			err := applyDifficulty()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not change the difficulty to %s: %v\n", settings.Difficulty, err)
+				settings.Difficulty = previous
+				for i, preset := range difficultyPresets {
+					if preset.Name == previous {
+						difficultySelected = i
+					}
+				}
+				applyDifficulty()
+			}
+		}),
+		NewMenuChoice("Words", packs, &wordPackSelected, func() {
+			settings.WordPack = currentWordPack().Name
+		}),
+		NewMenuChoice("Keyboard layout", layouts, &keyboardLayoutSelected, func() {
+			settings.KeyboardLayout = simulation.KeyboardLayouts[keyboardLayoutSelected].Name
+			gameConfig.KeyboardLayout = settings.KeyboardLayout
+		}),
+		NewMenuToggle("Finger hints", &settings.FingerHints, nil),
+		NewMenuToggle("On-screen keyboard", &settings.OnScreenKeyboard, nil),
+		NewMenuChoice("Frame rate limit", frameRateChoiceNames(), &frameRateSelected, func() {
+			frameRateLimit = frameRateChoices[frameRateSelected]
+			settings.FrameRateLimit = frameRateLimit
+			frameRateOverride = false
+		}),
+		NewMenuButton("Back", closeMenu),
+	)
+	settingsMenu.Close = saveSettings
+}
@@ -0,0 +1,172 @@
+package simulation
+
+type Asteroid struct {
+	id         int
+	alive      bool
+	destroyed  bool
+	targeted   bool
+	x          float32
+	y          float32
+	previousX  float32
+	previousY  float32
+	pathX      float32
+	pathY      float32
+	age        float32
+	size       float32
+	velocity   float32
+	trajectory Trajectory
+	kind       AsteroidKind
+	armor      []string
+	sentence   string
+	segments   int
+	variant    int
+	word       string
+}
+
+func NewAsteroid(x, y, size, velocity float32, trajectory Trajectory, variant int, word string) *Asteroid {
+	asteroid := &Asteroid{}
+	asteroid.alive = true
+	asteroid.destroyed = false
+	asteroid.targeted = false
+	asteroid.x = x
+	asteroid.y = y
+	asteroid.previousX = x
+	asteroid.previousY = y
+	asteroid.pathX = x
+	asteroid.pathY = y
+	asteroid.size = size
+	asteroid.velocity = velocity
+	asteroid.trajectory = trajectory
+	asteroid.variant = variant
+	asteroid.word = word
+	return asteroid
+}
+
+// ID numbers the asteroids of a game in the order they were spawned.
+func (asteroid *Asteroid) ID() int {
+	return asteroid.id
+}
+
+func (asteroid *Asteroid) X() float32 {
+	return asteroid.x
+}
+
+func (asteroid *Asteroid) Y() float32 {
+	return asteroid.y
+}
+
+// InterpolatedPosition returns the position between the last two updates,
+// where alpha 0 is the previous position and 1 the current one.
+func (asteroid *Asteroid) InterpolatedPosition(alpha float32) (float32, float32) {
+	x := asteroid.previousX + (asteroid.x-asteroid.previousX)*alpha
+	y := asteroid.previousY + (asteroid.y-asteroid.previousY)*alpha
+	return x, y
+}
+
+func (asteroid *Asteroid) Size() float32 {
+	return asteroid.size
+}
+
+func (asteroid *Asteroid) Movement() Movement {
+	return asteroid.trajectory.Movement
+}
+
+func (asteroid *Asteroid) Kind() AsteroidKind {
+	return asteroid.kind
+}
+
+// Armor returns how many more words have to be typed after the current one
+// before the asteroid is destroyed.
+func (asteroid *Asteroid) Armor() int {
+	return len(asteroid.armor)
+}
+
+// Sentence returns the whole sentence carried by a boss.
+func (asteroid *Asteroid) Sentence() string {
+	return asteroid.sentence
+}
+
+// Progress returns how much of a boss's sentence has been typed, from 0 to
+// 1.
+func (asteroid *Asteroid) Progress() float32 {
+	if asteroid.segments == 0 {
+		return 0.0
+	}
+	typed := asteroid.segments - 1 - len(asteroid.armor)
+	if asteroid.destroyed {
+		typed = asteroid.segments
+	}
+	return float32(typed) / float32(asteroid.segments)
+}
+
+// crack breaks a layer of armor and shows the next word.
+func (asteroid *Asteroid) crack() {
+	asteroid.word = asteroid.armor[0]
+	asteroid.armor = asteroid.armor[1:]
+	asteroid.targeted = false
+}
+
+func (asteroid *Asteroid) Variant() int {
+	return asteroid.variant
+}
+
+func (asteroid *Asteroid) Word() string {
+	return asteroid.word
+}
+
+func (asteroid *Asteroid) Destroy() {
+	asteroid.alive = false
+	asteroid.destroyed = true
+	asteroid.targeted = false
+}
+
+func (asteroid *Asteroid) Target() {
+	asteroid.targeted = true
+}
+
+func (asteroid *Asteroid) Untarget() {
+	asteroid.targeted = false
+}
+
+func (asteroid *Asteroid) IsTargeted() bool {
+	return asteroid.targeted
+}
+
+func (asteroid *Asteroid) IsAlive() bool {
+	return asteroid.alive
+}
+
+func (asteroid *Asteroid) WasDestroyed() bool {
+	return asteroid.destroyed
+}
+
+// Update moves the asteroid along its trajectory. Asteroids bounce off the
+// sides of the screen, so only those that fall past the bottom hit Earth.
+func (asteroid *Asteroid) Update(deltaTime float32, screenWidth float32, screenHeight float32) {
+	if !asteroid.alive {
+		return
+	}
+	asteroid.previousX = asteroid.x
+	asteroid.previousY = asteroid.y
+	asteroid.age += deltaTime
+
+	trajectory := &asteroid.trajectory
+	trajectory.steer(asteroid.x, asteroid.y, deltaTime)
+	asteroid.velocity += trajectory.Acceleration * deltaTime
+	asteroid.pathX += trajectory.HeadingX * asteroid.velocity * deltaTime
+	asteroid.pathY += trajectory.HeadingY * asteroid.velocity * deltaTime
+
+	half := asteroid.size / 2
+	if (asteroid.pathX < half && trajectory.HeadingX < 0.0) ||
+		(asteroid.pathX > screenWidth-half && trajectory.HeadingX > 0.0) {
+		trajectory.HeadingX = -trajectory.HeadingX
+	}
+
+	wobble := trajectory.wobble(asteroid.age)
+	asteroid.x = asteroid.pathX - trajectory.HeadingY*wobble
+	asteroid.y = asteroid.pathY + trajectory.HeadingX*wobble
+	if asteroid.y-half > screenHeight {
+		asteroid.alive = false
+		asteroid.targeted = false
+	}
+}
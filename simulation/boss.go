@@ -0,0 +1,66 @@
+package simulation
+
+import (
+	"math"
+	"strings"
+)
+
+// IsBossLevel reports whether the current level is a boss wave, where a
+// single large asteroid carries a whole sentence instead of a wave of
+// asteroids with one word each.
+func (game *Game) IsBossLevel() bool {
+	return game.config.BossEveryLevels > 0 && game.level%game.config.BossEveryLevels == 0
+}
+
+// Boss returns the boss asteroid while it is alive, and nil otherwise.
+func (game *Game) Boss() *Asteroid {
+	if game.boss == nil || !game.boss.IsAlive() {
+		return nil
+	}
+	return game.boss
+}
+
+// spawnBoss sends in the boss, which is typed one segment of its sentence
+// at a time, much like the layers of an armored asteroid. Every segment but
+// the last ends with the space after its word, so the whole sentence is
+// typed, spaces included.
+func (game *Game) spawnBoss() {
+	sentence := strings.Join(strings.Fields(game.words.RandomSentence(game.random, game.level)), " ")
+	segments := strings.SplitAfter(sentence, " ")
+	x := game.config.Width / 2
+	y := game.config.StartAsteroidY
+	velocity := game.asteroidVelocity * game.config.BossVelocityMultiplier
+	variant := game.random.Intn(game.config.AsteroidVariants)
+	boss := NewAsteroid(x, y, game.config.BossSize, velocity, StraightDown(), variant, segments[0])
+	boss.kind = AsteroidBoss
+	boss.armor = segments[1:]
+	boss.sentence = sentence
+	boss.segments = len(segments)
+	game.boss = boss
+	game.bossShedTimeLeft = game.config.BossShedDelay
+	game.addAsteroid(boss)
+	game.asteroidsLeftToSpawn--
+}
+
+// shed breaks a small asteroid with a short word off the boss every
+// BossShedDelay milliseconds for as long as the boss is alive.
+func (game *Game) shed(deltaTime float32) {
+	boss := game.Boss()
+	if boss == nil || game.config.BossShedDelay <= 0 {
+		return
+	}
+	game.bossShedTimeLeft -= deltaTime
+	if game.bossShedTimeLeft > 0 {
+		return
+	}
+	game.bossShedTimeLeft += game.config.BossShedDelay
+
+	word := game.words.ShortWord(game.random, game.config.BossShedMaxWordLength)
+	angle := (game.random.Float64()*2.0 - 1.0) * float64(game.config.DiagonalMaxAngle) * math.Pi / 180.0
+	sin, cos := math.Sincos(angle)
+	trajectory := StraightDown()
+	trajectory.setHeading(float32(sin), float32(cos))
+	shed := NewAsteroid(boss.x, boss.y+boss.size/4, game.asteroidSize(word), game.asteroidVelocity,
+		trajectory, game.random.Intn(game.config.AsteroidVariants), word)
+	game.addAsteroid(shed)
+}
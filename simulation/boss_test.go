@@ -0,0 +1,49 @@
+package simulation
+
+import "testing"
+
+func TestBossSentenceIsTypedWithSpaces(t *testing.T) {
+	config := DefaultConfig()
+	config.BossEveryLevels = 1
+	config.BossShedDelay = 0
+	pack := &WordPack{
+		Name:       "test",
+		Characters: LowercaseCharacters,
+		Words:      []string{"ship", "rock", "star"},
+		Sentences:  []string{"the quick  brown fox"},
+	}
+	destroyed := false
+	game := NewGame(config)
+	game.Start(1, pack, Callbacks{
+		AsteroidDestroyed: func(asteroid *Asteroid) {
+			destroyed = asteroid.Kind() == AsteroidBoss
+		},
+	})
+	game.Update(config.StartDelayBetweenAsteroids)
+
+	boss := game.Boss()
+	if boss == nil {
+		t.Fatal("no boss on a boss level")
+	}
+	if boss.Sentence() != "the quick brown fox" {
+		t.Fatalf("got sentence %q, want %q", boss.Sentence(), "the quick brown fox")
+	}
+	if boss.Word() != "the " {
+		t.Fatalf("got segment %q, want %q", boss.Word(), "the ")
+	}
+
+	for _, character := range "the quick brown fox" {
+		if !game.Type(character) {
+			t.Fatalf("typing %q after %q was a mistype, boss shows %q", character, game.Input(), boss.Word())
+		}
+		if character == ' ' && game.Input() != "" {
+			t.Fatalf("got input %q after a space, want the segment to be done", game.Input())
+		}
+	}
+	if !destroyed || game.Boss() != nil {
+		t.Errorf("boss survived its whole sentence, %q left", boss.Word())
+	}
+	if boss.Progress() != 1.0 {
+		t.Errorf("got progress %v, want 1", boss.Progress())
+	}
+}
@@ -0,0 +1,195 @@
+package simulation
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	wordDifficultyLengthWeight     float64 = 1.0
+	wordDifficultyRareLetterWeight float64 = 1.0
+	wordDifficultyBigramWeight     float64 = 0.75
+	wordDifficultySameHandWeight   float64 = 0.35
+	wordDifficultyShiftWeight      float64 = 0.5
+
+	levelDifficultyStart     float64 = 4.0
+	levelDifficultyIncrement float64 = 0.5
+	levelDifficultyBand      float64 = 1.5
+	levelDifficultyMinWords  int     = 4
+
+	bossSentenceWords int = 8
+
+	letterFrequencies = map[rune]float64{
+		'a': 8.2, 'b': 1.5, 'c': 2.8, 'd': 4.3, 'e': 12.7, 'f': 2.2,
+		'g': 2.0, 'h': 6.1, 'i': 7.0, 'j': 0.15, 'k': 0.77, 'l': 4.0,
+		'm': 2.4, 'n': 6.7, 'o': 7.5, 'p': 1.9, 'q': 0.095, 'r': 6.0,
+		's': 6.3, 't': 9.1, 'u': 2.8, 'v': 0.98, 'w': 2.4, 'x': 0.15,
+		'y': 2.0, 'z': 0.074,
+	}
+	maxLetterFrequency float64 = 12.7
+)
+
+func letterRarity(letter rune) float64 {
+	frequency, ok := letterFrequencies[unicode.ToLower(letter)]
+	if !ok {
+		return 1.0
+	}
+	return 1.0 - (frequency / maxLetterFrequency)
+}
+
+func bigramDifficulty(layout *KeyboardLayout, first, second rune) float64 {
+	if first == second {
+		return 0.0
+	}
+	difficulty := 0.0
+	if !layout.hasKey(first) || !layout.hasKey(second) {
+		return difficulty
+	}
+	if layout.Finger(first) == layout.Finger(second) {
+		difficulty += 1.0
+	}
+	rowDistance := layout.Row(first) - layout.Row(second)
+	if rowDistance == 2 || rowDistance == -2 {
+		difficulty += 0.5
+	}
+	return difficulty
+}
+
+func wordDifficulty(layout *KeyboardLayout, word string) float64 {
+	letters := []rune(word)
+	rarity := 0.0
+	bigrams := 0.0
+	sameHand := 0.0
+	shifted := 0.0
+	for i, letter := range letters {
+		rarity += letterRarity(letter)
+		if _, shift := layout.Key(letter); shift {
+			shifted += 1.0
+		}
+		if i > 0 {
+			previous := letters[i-1]
+			bigrams += bigramDifficulty(layout, previous, letter)
+			hand := layout.Hand(letter)
+			if hand >= 0 && layout.Hand(previous) == hand {
+				sameHand += 1.0
+			}
+		}
+	}
+	return float64(len(letters))*wordDifficultyLengthWeight +
+		rarity*wordDifficultyRareLetterWeight +
+		bigrams*wordDifficultyBigramWeight +
+		sameHand*wordDifficultySameHandWeight +
+		shifted*wordDifficultyShiftWeight
+}
+
+func levelDifficulty(level int) float64 {
+	return levelDifficultyStart + float64(level-1)*levelDifficultyIncrement
+}
+
+// rankWords sorts the words of the pack by how hard they are to type on
+// the given keyboard layout.
+func (pack *WordPack) rankWords(layout *KeyboardLayout) {
+	if pack.layout == layout {
+		return
+	}
+	pack.layout = layout
+	pack.difficulties = make([]float64, len(pack.Words))
+	for i, word := range pack.Words {
+		pack.difficulties[i] = wordDifficulty(layout, word)
+	}
+	sort.Sort(wordPackByDifficulty{pack})
+}
+
+type wordPackByDifficulty struct {
+	pack *WordPack
+}
+
+func (words wordPackByDifficulty) Len() int {
+	return len(words.pack.Words)
+}
+
+// Words of the same difficulty are sorted alphabetically, so that the
+// order does not depend on which layout the pack was ranked for before.
+func (words wordPackByDifficulty) Less(i, j int) bool {
+	if words.pack.difficulties[i] == words.pack.difficulties[j] {
+		return words.pack.Words[i] < words.pack.Words[j]
+	}
+	return words.pack.difficulties[i] < words.pack.difficulties[j]
+}
+
+func (words wordPackByDifficulty) Swap(i, j int) {
+	words.pack.Words[i], words.pack.Words[j] = words.pack.Words[j], words.pack.Words[i]
+	words.pack.difficulties[i], words.pack.difficulties[j] = words.pack.difficulties[j], words.pack.difficulties[i]
+}
+
+// The words of a level are drawn from a band around the level's target
+// difficulty. When the band runs past the hardest word in the pack, words
+// are joined together until they are hard enough, so there is no ceiling.
+func (pack *WordPack) RandomWord(random *rand.Rand, level int) string {
+	target := levelDifficulty(level)
+	hardest := pack.difficulties[len(pack.difficulties)-1]
+	if target-levelDifficultyBand > hardest {
+		return pack.compoundWord(random, target)
+	}
+
+	first := sort.SearchFloat64s(pack.difficulties, target-levelDifficultyBand)
+	last := sort.SearchFloat64s(pack.difficulties, target+levelDifficultyBand)
+	for last-first < levelDifficultyMinWords && last-first < len(pack.Words) {
+		if first > 0 && (last >= len(pack.Words) ||
+			target-pack.difficulties[first-1] < pack.difficulties[last]-target) {
+			first--
+		} else {
+			last++
+		}
+	}
+	return pack.Words[random.Intn(last-first)+first]
+}
+
+// RandomSentence returns one of the sentences of the pack for a boss. A
+// pack without sentences gets one made of words of the level instead.
+func (pack *WordPack) RandomSentence(random *rand.Rand, level int) string {
+	if len(pack.Sentences) > 0 {
+		return pack.Sentences[random.Intn(len(pack.Sentences))]
+	}
+	words := make([]string, bossSentenceWords)
+	for i := range words {
+		words[i] = pack.RandomWord(random, level)
+	}
+	return strings.Join(words, " ")
+}
+
+// ShortWord returns a random word of at most maxLength letters, or one of
+// the shortest words when the pack has none that short.
+func (pack *WordPack) ShortWord(random *rand.Rand, maxLength int) string {
+	shortest := utf8.RuneCountInString(pack.Words[0])
+	for _, word := range pack.Words {
+		if utf8.RuneCountInString(word) < shortest {
+			shortest = utf8.RuneCountInString(word)
+		}
+	}
+	if maxLength < shortest {
+		maxLength = shortest
+	}
+	words := make([]string, 0)
+	for _, word := range pack.Words {
+		if utf8.RuneCountInString(word) <= maxLength {
+			words = append(words, word)
+		}
+	}
+	return words[random.Intn(len(words))]
+}
+
+func (pack *WordPack) compoundWord(random *rand.Rand, target float64) string {
+	hardest := len(pack.Words) - levelDifficultyMinWords
+	if hardest < 0 {
+		hardest = 0
+	}
+	word := pack.Words[random.Intn(len(pack.Words)-hardest)+hardest]
+	for wordDifficulty(pack.layout, word) < target-levelDifficultyBand {
+		word += pack.Words[random.Intn(len(pack.Words))]
+	}
+	return word
+}
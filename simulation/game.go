@@ -0,0 +1,905 @@
+// Package simulation contains the rules of Astrotyper without any rendering,
+// so that a game can be played, tested and replayed without a display.
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/snosscire/astrotyper/stats"
+)
+
+type Config struct {
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+
+	StartNumberOfAsteroids     int     `json:"startNumberOfAsteroids"`
+	StartDelayBetweenAsteroids float32 `json:"startDelayBetweenAsteroids"`
+	StartAsteroidVelocity      float32 `json:"startAsteroidVelocity"`
+	StartAsteroidY             float32 `json:"startAsteroidY"`
+
+	AsteroidsToSpawnIncrement      int     `json:"asteroidsToSpawnIncrement"`
+	DelayBetweenAsteroidsIncrement float32 `json:"delayBetweenAsteroidsIncrement"`
+	AsteroidVelocityIncrement      float32 `json:"asteroidVelocityIncrement"`
+
+	MinDelayBetweenAsteroids float32 `json:"minDelayBetweenAsteroids"`
+
+	AsteroidMinDamage        int     `json:"asteroidMinDamage"`
+	AsteroidMaxDamage        int     `json:"asteroidMaxDamage"`
+	AsteroidSize             float32 `json:"asteroidSize"`
+	AsteroidSizePerLetter    float32 `json:"asteroidSizePerLetter"`
+	AsteroidMaxSize          float32 `json:"asteroidMaxSize"`
+	AsteroidVariants         int     `json:"asteroidVariants"`
+	AsteroidSpawnMarginLeft  float32 `json:"asteroidSpawnMarginLeft"`
+	AsteroidSpawnMarginRight float32 `json:"asteroidSpawnMarginRight"`
+
+	// The level each kind of movement first appears at, 0 for never.
+	DiagonalFromLevel     int     `json:"diagonalFromLevel"`
+	WobbleFromLevel       int     `json:"wobbleFromLevel"`
+	AcceleratingFromLevel int     `json:"acceleratingFromLevel"`
+	HomingFromLevel       int     `json:"homingFromLevel"`
+	SideSpawnFromLevel    int     `json:"sideSpawnFromLevel"`
+	SideSpawnChance       float32 `json:"sideSpawnChance"`
+	DiagonalMaxAngle      float32 `json:"diagonalMaxAngle"`
+	WobbleAmplitude       float32 `json:"wobbleAmplitude"`
+	WobblePeriod          float32 `json:"wobblePeriod"`
+	AsteroidAcceleration  float32 `json:"asteroidAcceleration"`
+	HomingTurnRate        float32 `json:"homingTurnRate"`
+
+	// How often each kind of asteroid appears compared to the others, and
+	// the level it first appears at, 0 for never.
+	NormalWeight      int `json:"normalWeight"`
+	SplitterWeight    int `json:"splitterWeight"`
+	SplitterFromLevel int `json:"splitterFromLevel"`
+	ArmoredWeight     int `json:"armoredWeight"`
+	ArmoredFromLevel  int `json:"armoredFromLevel"`
+	FastWeight        int `json:"fastWeight"`
+	FastFromLevel     int `json:"fastFromLevel"`
+	SupplyWeight      int `json:"supplyWeight"`
+	SupplyFromLevel   int `json:"supplyFromLevel"`
+
+	// Points for each letter of a word on each kind of asteroid, before
+	// the level and the combo multiplier are applied.
+	NormalScore   int `json:"normalScore"`
+	SplitterScore int `json:"splitterScore"`
+	ArmoredScore  int `json:"armoredScore"`
+	FastScore     int `json:"fastScore"`
+	SupplyScore   int `json:"supplyScore"`
+
+	SplitterFragments      int     `json:"splitterFragments"`
+	FragmentMaxWordLength  int     `json:"fragmentMaxWordLength"`
+	ArmoredWords           int     `json:"armoredWords"`
+	FastMaxWordLength      int     `json:"fastMaxWordLength"`
+	FastVelocityMultiplier float32 `json:"fastVelocityMultiplier"`
+	SupplyHealth           int     `json:"supplyHealth"`
+
+	// Every BossEveryLevels levels, 0 for never, the wave is a single boss
+	// carrying a sentence. It sheds a small asteroid every BossShedDelay
+	// milliseconds and does BossDamage when it reaches Earth.
+	BossEveryLevels        int     `json:"bossEveryLevels"`
+	BossSize               float32 `json:"bossSize"`
+	BossVelocityMultiplier float32 `json:"bossVelocityMultiplier"`
+	BossScore              int     `json:"bossScore"`
+	BossDamage             int     `json:"bossDamage"`
+	BossShedDelay          float32 `json:"bossShedDelay"`
+	BossShedMaxWordLength  int     `json:"bossShedMaxWordLength"`
+
+	ShipX float32 `json:"shipX"`
+	ShipY float32 `json:"shipY"`
+
+	PlayerStartHealth int `json:"playerStartHealth"`
+
+	ComboWordsPerMultiplier int     `json:"comboWordsPerMultiplier"`
+	ComboMaxMultiplier      int     `json:"comboMaxMultiplier"`
+	MistypeBreaksCombo      bool    `json:"mistypeBreaksCombo"`
+	MistypeLockout          float32 `json:"mistypeLockout"`
+	MistypeDamage           int     `json:"mistypeDamage"`
+
+	KeyboardLayout string `json:"keyboardLayout"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Width:  1920,
+		Height: 1080,
+
+		StartNumberOfAsteroids:     5,
+		StartDelayBetweenAsteroids: 2000.0,
+		StartAsteroidVelocity:      0.1,
+		StartAsteroidY:             -64.0,
+
+		AsteroidsToSpawnIncrement:      1,
+		DelayBetweenAsteroidsIncrement: -100.0,
+		AsteroidVelocityIncrement:      0.01,
+
+		MinDelayBetweenAsteroids: 1000.0,
+
+		AsteroidMinDamage:        5,
+		AsteroidMaxDamage:        10,
+		AsteroidSize:             56,
+		AsteroidSizePerLetter:    8,
+		AsteroidMaxSize:          160,
+		AsteroidVariants:         1,
+		AsteroidSpawnMarginLeft:  64,
+		AsteroidSpawnMarginRight: 448,
+
+		DiagonalFromLevel:     2,
+		WobbleFromLevel:       3,
+		AcceleratingFromLevel: 4,
+		HomingFromLevel:       6,
+		SideSpawnFromLevel:    5,
+		SideSpawnChance:       0.25,
+		DiagonalMaxAngle:      25.0,
+		WobbleAmplitude:       60.0,
+		WobblePeriod:          3000.0,
+		AsteroidAcceleration:  0.00002,
+		HomingTurnRate:        0.02,
+
+		NormalWeight:      12,
+		SplitterWeight:    2,
+		SplitterFromLevel: 2,
+		ArmoredWeight:     2,
+		ArmoredFromLevel:  3,
+		FastWeight:        3,
+		FastFromLevel:     2,
+		SupplyWeight:      1,
+		SupplyFromLevel:   3,
+
+		NormalScore:   10,
+		SplitterScore: 15,
+		ArmoredScore:  20,
+		FastScore:     25,
+		SupplyScore:   5,
+
+		SplitterFragments:      2,
+		FragmentMaxWordLength:  4,
+		ArmoredWords:           2,
+		FastMaxWordLength:      4,
+		FastVelocityMultiplier: 1.8,
+		SupplyHealth:           10,
+
+		BossEveryLevels:        5,
+		BossSize:               320,
+		BossVelocityMultiplier: 0.25,
+		BossScore:              20,
+		BossDamage:             40,
+		BossShedDelay:          4000,
+		BossShedMaxWordLength:  5,
+
+		ShipX: 960,
+		ShipY: 920,
+
+		PlayerStartHealth: 100,
+
+		ComboWordsPerMultiplier: 3,
+		ComboMaxMultiplier:      8,
+		MistypeBreaksCombo:      true,
+		MistypeLockout:          0.0,
+		MistypeDamage:           0,
+
+		KeyboardLayout: DefaultKeyboardLayout,
+	}
+}
+
+// Validate reports the first setting that would make the game unplayable,
+// such as a damage range that is reversed or a combo that never grows.
+func (config Config) Validate() error {
+	if config.StartNumberOfAsteroids < 1 {
+		return fmt.Errorf("startNumberOfAsteroids must be at least 1, got %d", config.StartNumberOfAsteroids)
+	}
+	if config.StartDelayBetweenAsteroids < 0 {
+		return fmt.Errorf("startDelayBetweenAsteroids must not be negative, got %v", config.StartDelayBetweenAsteroids)
+	}
+	if config.StartAsteroidVelocity <= 0 {
+		return fmt.Errorf("startAsteroidVelocity must be greater than 0, got %v", config.StartAsteroidVelocity)
+	}
+	if config.AsteroidsToSpawnIncrement < 0 {
+		return fmt.Errorf("asteroidsToSpawnIncrement must not be negative, got %d", config.AsteroidsToSpawnIncrement)
+	}
+	if config.AsteroidVelocityIncrement < 0 {
+		return fmt.Errorf("asteroidVelocityIncrement must not be negative, got %v", config.AsteroidVelocityIncrement)
+	}
+	if config.MinDelayBetweenAsteroids < 0 {
+		return fmt.Errorf("minDelayBetweenAsteroids must not be negative, got %v", config.MinDelayBetweenAsteroids)
+	}
+	if config.AsteroidMinDamage < 0 || config.AsteroidMaxDamage > 100 {
+		return fmt.Errorf("asteroid damage must be between 0 and 100, got %d to %d",
+			config.AsteroidMinDamage, config.AsteroidMaxDamage)
+	}
+	if config.AsteroidMinDamage > config.AsteroidMaxDamage {
+		return fmt.Errorf("asteroidMinDamage %d is greater than asteroidMaxDamage %d",
+			config.AsteroidMinDamage, config.AsteroidMaxDamage)
+	}
+	if config.AsteroidSize <= 0 {
+		return fmt.Errorf("asteroidSize must be greater than 0, got %v", config.AsteroidSize)
+	}
+	if config.AsteroidSizePerLetter < 0 {
+		return fmt.Errorf("asteroidSizePerLetter must not be negative, got %v", config.AsteroidSizePerLetter)
+	}
+	if config.AsteroidMaxSize < config.AsteroidSize {
+		return fmt.Errorf("asteroidMaxSize %v is less than asteroidSize %v",
+			config.AsteroidMaxSize, config.AsteroidSize)
+	}
+	if config.AsteroidVariants < 1 {
+		return fmt.Errorf("asteroidVariants must be at least 1, got %d", config.AsteroidVariants)
+	}
+	if config.AsteroidSpawnMarginLeft < 0 || config.AsteroidSpawnMarginRight < 0 {
+		return fmt.Errorf("asteroid spawn margins must not be negative, got %v and %v",
+			config.AsteroidSpawnMarginLeft, config.AsteroidSpawnMarginRight)
+	}
+	if config.AsteroidSpawnMarginLeft+config.AsteroidSpawnMarginRight >= config.Width {
+		return fmt.Errorf("asteroid spawn margins %v and %v leave no room on a screen %v wide",
+			config.AsteroidSpawnMarginLeft, config.AsteroidSpawnMarginRight, config.Width)
+	}
+	fromLevels := []struct {
+		name  string
+		level int
+	}{
+		{"diagonalFromLevel", config.DiagonalFromLevel},
+		{"wobbleFromLevel", config.WobbleFromLevel},
+		{"acceleratingFromLevel", config.AcceleratingFromLevel},
+		{"homingFromLevel", config.HomingFromLevel},
+		{"sideSpawnFromLevel", config.SideSpawnFromLevel},
+		{"splitterFromLevel", config.SplitterFromLevel},
+		{"armoredFromLevel", config.ArmoredFromLevel},
+		{"fastFromLevel", config.FastFromLevel},
+		{"supplyFromLevel", config.SupplyFromLevel},
+	}
+	for _, fromLevel := range fromLevels {
+		if fromLevel.level < 0 {
+			return fmt.Errorf("%s must not be negative, got %d", fromLevel.name, fromLevel.level)
+		}
+	}
+	counts := []struct {
+		name  string
+		count int
+	}{
+		{"normalWeight", config.NormalWeight},
+		{"splitterWeight", config.SplitterWeight},
+		{"armoredWeight", config.ArmoredWeight},
+		{"fastWeight", config.FastWeight},
+		{"supplyWeight", config.SupplyWeight},
+		{"normalScore", config.NormalScore},
+		{"splitterScore", config.SplitterScore},
+		{"armoredScore", config.ArmoredScore},
+		{"fastScore", config.FastScore},
+		{"supplyScore", config.SupplyScore},
+		{"splitterFragments", config.SplitterFragments},
+		{"supplyHealth", config.SupplyHealth},
+		{"bossEveryLevels", config.BossEveryLevels},
+		{"bossScore", config.BossScore},
+	}
+	for _, count := range counts {
+		if count.count < 0 {
+			return fmt.Errorf("%s must not be negative, got %d", count.name, count.count)
+		}
+	}
+	if config.ArmoredWords < 1 {
+		return fmt.Errorf("armoredWords must be at least 1, got %d", config.ArmoredWords)
+	}
+	if config.FragmentMaxWordLength < 1 || config.FastMaxWordLength < 1 {
+		return fmt.Errorf("fragmentMaxWordLength and fastMaxWordLength must be at least 1, got %d and %d",
+			config.FragmentMaxWordLength, config.FastMaxWordLength)
+	}
+	if config.BossSize <= 0 {
+		return fmt.Errorf("bossSize must be greater than 0, got %v", config.BossSize)
+	}
+	if config.BossVelocityMultiplier <= 0 {
+		return fmt.Errorf("bossVelocityMultiplier must be greater than 0, got %v", config.BossVelocityMultiplier)
+	}
+	if config.BossDamage < 0 || config.BossDamage > 100 {
+		return fmt.Errorf("bossDamage must be between 0 and 100, got %d", config.BossDamage)
+	}
+	if config.BossShedDelay < 0 {
+		return fmt.Errorf("bossShedDelay must not be negative, got %v", config.BossShedDelay)
+	}
+	if config.BossShedMaxWordLength < 1 {
+		return fmt.Errorf("bossShedMaxWordLength must be at least 1, got %d", config.BossShedMaxWordLength)
+	}
+	if config.FastVelocityMultiplier <= 0 {
+		return fmt.Errorf("fastVelocityMultiplier must be greater than 0, got %v", config.FastVelocityMultiplier)
+	}
+	if config.SideSpawnChance < 0 || config.SideSpawnChance > 1 {
+		return fmt.Errorf("sideSpawnChance must be between 0 and 1, got %v", config.SideSpawnChance)
+	}
+	if config.DiagonalMaxAngle < 0 || config.DiagonalMaxAngle >= 90 {
+		return fmt.Errorf("diagonalMaxAngle must be between 0 and 90 degrees, got %v", config.DiagonalMaxAngle)
+	}
+	if config.WobbleAmplitude < 0 {
+		return fmt.Errorf("wobbleAmplitude must not be negative, got %v", config.WobbleAmplitude)
+	}
+	if config.WobblePeriod <= 0 {
+		return fmt.Errorf("wobblePeriod must be greater than 0, got %v", config.WobblePeriod)
+	}
+	if config.AsteroidAcceleration < 0 {
+		return fmt.Errorf("asteroidAcceleration must not be negative, got %v", config.AsteroidAcceleration)
+	}
+	if config.HomingTurnRate < 0 {
+		return fmt.Errorf("homingTurnRate must not be negative, got %v", config.HomingTurnRate)
+	}
+	if config.ShipX < 0 || config.ShipX > config.Width || config.ShipY < 0 || config.ShipY > config.Height {
+		return fmt.Errorf("ship position %v,%v is outside the screen", config.ShipX, config.ShipY)
+	}
+	if config.PlayerStartHealth < 1 {
+		return fmt.Errorf("playerStartHealth must be at least 1, got %d", config.PlayerStartHealth)
+	}
+	if config.ComboWordsPerMultiplier < 1 {
+		return fmt.Errorf("comboWordsPerMultiplier must be at least 1, got %d", config.ComboWordsPerMultiplier)
+	}
+	if config.ComboMaxMultiplier < 1 {
+		return fmt.Errorf("comboMaxMultiplier must be at least 1, got %d", config.ComboMaxMultiplier)
+	}
+	if config.MistypeLockout < 0 {
+		return fmt.Errorf("mistypeLockout must not be negative, got %v", config.MistypeLockout)
+	}
+	if config.MistypeDamage < 0 || config.MistypeDamage > 100 {
+		return fmt.Errorf("mistypeDamage must be between 0 and 100, got %d", config.MistypeDamage)
+	}
+	if FindKeyboardLayout(config.KeyboardLayout) == nil {
+		return fmt.Errorf("unknown keyboardLayout %q", config.KeyboardLayout)
+	}
+	return nil
+}
+
+type AsteroidNotDestroyed func(*Asteroid, int)
+type AsteroidDestroyed func(*Asteroid)
+type NextLevel func(int)
+type GameOver func()
+type Mistype func(rune)
+type TargetLocked func(*Asteroid)
+type AsteroidCracked func(*Asteroid)
+
+type Callbacks struct {
+	AsteroidNotDestroyed AsteroidNotDestroyed
+	AsteroidDestroyed    AsteroidDestroyed
+	NextLevel            NextLevel
+	GameOver             GameOver
+	Mistype              Mistype
+	TargetLocked         TargetLocked
+	AsteroidCracked      AsteroidCracked
+}
+
+type Game struct {
+	config                     Config
+	seed                       int64
+	random                     *rand.Rand
+	words                      *WordPack
+	callbacks                  Callbacks
+	player                     *Player
+	asteroids                  []*Asteroid
+	asteroidsSpawned           int
+	level                      int
+	score                      int
+	over                       bool
+	input                      string
+	target                     *Asteroid
+	time                       float32
+	stats                      *stats.Recorder
+	layout                     *KeyboardLayout
+	combo                      int
+	wordMistyped               bool
+	lockout                    float32
+	numberOfAsteroidsToSpawn   int
+	asteroidsLeftToSpawn       int
+	delayBetweenAsteroids      float32
+	timeUntilNextAsteroidSpawn float32
+	asteroidVelocity           float32
+	boss                       *Asteroid
+	bossShedTimeLeft           float32
+}
+
+func NewGame(config Config) *Game {
+	game := &Game{}
+	game.config = config
+	game.player = NewPlayer(config.PlayerStartHealth)
+	game.stats = stats.NewRecorder()
+	return game
+}
+
+func (game *Game) Start(seed int64, words *WordPack, callbacks Callbacks) {
+	game.seed = seed
+	game.random = rand.New(rand.NewSource(seed))
+	game.words = words
+	game.layout = FindKeyboardLayout(game.config.KeyboardLayout)
+	game.words.rankWords(game.layout)
+	game.callbacks = callbacks
+	game.player.Reset()
+	game.level = 1
+	game.score = 0
+	game.over = false
+	game.input = ""
+	game.target = nil
+	game.time = 0.0
+	game.stats.Reset()
+	game.combo = 0
+	game.wordMistyped = false
+	game.lockout = 0.0
+	game.numberOfAsteroidsToSpawn = game.config.StartNumberOfAsteroids
+	game.asteroidsLeftToSpawn = game.numberOfAsteroidsToSpawn
+	game.delayBetweenAsteroids = game.config.StartDelayBetweenAsteroids
+	game.timeUntilNextAsteroidSpawn = game.delayBetweenAsteroids
+	game.asteroidVelocity = game.config.StartAsteroidVelocity
+	game.asteroids = make([]*Asteroid, 0)
+	game.asteroidsSpawned = 0
+	game.boss = nil
+	game.startWave()
+}
+
+func (game *Game) Config() Config {
+	return game.config
+}
+
+func (game *Game) Seed() int64 {
+	return game.seed
+}
+
+func (game *Game) Player() *Player {
+	return game.player
+}
+
+func (game *Game) Asteroids() []*Asteroid {
+	return game.asteroids
+}
+
+func (game *Game) Level() int {
+	return game.level
+}
+
+func (game *Game) Score() int {
+	return game.score
+}
+
+func (game *Game) IsOver() bool {
+	return game.over
+}
+
+func (game *Game) Input() string {
+	return game.input
+}
+
+func (game *Game) Target() *Asteroid {
+	return game.target
+}
+
+func (game *Game) Time() float32 {
+	return game.time
+}
+
+func (game *Game) Combo() int {
+	return game.combo
+}
+
+func (game *Game) Multiplier() int {
+	multiplier := 1
+	if game.config.ComboWordsPerMultiplier > 0 {
+		multiplier += game.combo / game.config.ComboWordsPerMultiplier
+	}
+	if game.config.ComboMaxMultiplier > 0 && multiplier > game.config.ComboMaxMultiplier {
+		multiplier = game.config.ComboMaxMultiplier
+	}
+	return multiplier
+}
+
+func (game *Game) IsLockedOut() bool {
+	return game.lockout > 0.0
+}
+
+// Layout returns the keyboard layout the game was started with.
+func (game *Game) Layout() *KeyboardLayout {
+	return game.layout
+}
+
+// NextCharacter returns the character to type next in the word of the
+// targeted asteroid, or 0 when no asteroid is targeted.
+func (game *Game) NextCharacter() rune {
+	if game.target == nil || len(game.input) >= len(game.target.word) {
+		return 0
+	}
+	character, _ := utf8.DecodeRuneInString(game.target.word[len(game.input):])
+	return character
+}
+
+func (game *Game) Stats() *stats.Recorder {
+	return game.stats
+}
+
+func (game *Game) WordsPerMinute() float64 {
+	return game.stats.WordsPerMinute()
+}
+
+func (game *Game) Accuracy() float64 {
+	return game.stats.Accuracy()
+}
+
+// GetMatchingAsteroids returns the live asteroids whose words start with
+// prefix, the ones closest to Earth first.
+func (game *Game) GetMatchingAsteroids(prefix string) []*Asteroid {
+	matching := make([]*Asteroid, 0)
+	for _, asteroid := range game.asteroids {
+		if asteroid.IsAlive() && !asteroid.WasDestroyed() {
+			if strings.HasPrefix(asteroid.word, prefix) {
+				matching = append(matching, asteroid)
+			}
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].y > matching[j].y
+	})
+	return matching
+}
+
+func (game *Game) GetMatchingAsteroid(prefix string) *Asteroid {
+	matching := game.GetMatchingAsteroids(prefix)
+	if len(matching) == 0 {
+		return nil
+	}
+	return matching[0]
+}
+
+func (game *Game) Type(character rune) bool {
+	if game.over || game.IsLockedOut() {
+		return false
+	}
+	// No word starts with a space, so the space typed out of habit after
+	// finishing a word is not a mistake.
+	if character == ' ' && game.input == "" {
+		return false
+	}
+	var previous rune
+	if len(game.input) > 0 {
+		previous, _ = utf8.DecodeLastRuneInString(game.input)
+	}
+	expected := game.NextCharacter()
+	correct := game.typeCharacter(character)
+	if correct {
+		expected = character
+	}
+	finger := -1
+	if expected != 0 {
+		finger = game.layout.Finger(expected)
+	}
+	game.stats.Record(game.time, character, expected, previous, finger, correct)
+	if !correct {
+		game.mistype(character)
+	}
+	return correct
+}
+
+func (game *Game) mistype(character rune) {
+	game.wordMistyped = true
+	if game.config.MistypeBreaksCombo {
+		game.combo = 0
+	}
+	game.lockout = game.config.MistypeLockout
+	game.player.TakeDamage(game.config.MistypeDamage)
+	if game.callbacks.Mistype != nil {
+		game.callbacks.Mistype(character)
+	}
+	game.checkGameOver()
+}
+
+// The current target is kept as long as the typed prefix matches its word.
+// Otherwise the asteroid closest to Earth that matches the prefix becomes
+// the new target.
+func (game *Game) typeCharacter(character rune) bool {
+	prefix := game.input + string(character)
+	if game.target == nil || !strings.HasPrefix(game.target.word, prefix) {
+		asteroid := game.GetMatchingAsteroid(prefix)
+		if asteroid == nil {
+			return false
+		}
+		game.setTarget(asteroid)
+	}
+	game.input = prefix
+	if game.input == game.target.word {
+		game.destroyTarget()
+	}
+	return true
+}
+
+func (game *Game) setTarget(asteroid *Asteroid) {
+	if game.target != nil {
+		game.target.Untarget()
+	}
+	game.target = asteroid
+	game.target.Target()
+	if game.callbacks.TargetLocked != nil {
+		game.callbacks.TargetLocked(asteroid)
+	}
+}
+
+// CycleTarget moves the target to the next asteroid that matches what has
+// been typed so far.
+func (game *Game) CycleTarget() bool {
+	if game.over || game.target == nil {
+		return false
+	}
+	matching := game.GetMatchingAsteroids(game.input)
+	if len(matching) < 2 {
+		return false
+	}
+	next := 0
+	for i, asteroid := range matching {
+		if asteroid == game.target {
+			next = (i + 1) % len(matching)
+		}
+	}
+	game.setTarget(matching[next])
+	return true
+}
+
+func (game *Game) Backspace() {
+	if len(game.input) == 0 {
+		return
+	}
+	_, size := utf8.DecodeLastRuneInString(game.input)
+	game.input = game.input[:len(game.input)-size]
+	if len(game.input) == 0 {
+		game.ClearInput()
+	}
+}
+
+func (game *Game) ClearInput() {
+	game.input = ""
+	game.wordMistyped = false
+	if game.target != nil {
+		game.target.Untarget()
+		game.target = nil
+	}
+}
+
+// destroyTarget is called when the word of the target has been typed. An
+// armored asteroid loses a layer of armor and shows its next word instead.
+func (game *Game) destroyTarget() {
+	asteroid := game.target
+	if !game.wordMistyped {
+		game.combo++
+	}
+	game.score += utf8.RuneCountInString(asteroid.word) * game.level * game.kindScore(asteroid.kind) * game.Multiplier()
+	game.target = nil
+	game.input = ""
+	game.wordMistyped = false
+	if asteroid.Armor() > 0 {
+		asteroid.crack()
+		if game.callbacks.AsteroidCracked != nil {
+			game.callbacks.AsteroidCracked(asteroid)
+		}
+		return
+	}
+	asteroid.Destroy()
+	switch asteroid.kind {
+	case AsteroidSplitter:
+		game.splitAsteroid(asteroid)
+	case AsteroidSupply:
+		game.player.Heal(game.config.SupplyHealth)
+	}
+	if game.callbacks.AsteroidDestroyed != nil {
+		game.callbacks.AsteroidDestroyed(asteroid)
+	}
+}
+
+func (game *Game) asteroidDamage() int {
+	if game.config.AsteroidMaxDamage == game.config.AsteroidMinDamage {
+		return game.config.AsteroidMinDamage
+	}
+	damage := game.random.Intn(game.config.AsteroidMaxDamage - game.config.AsteroidMinDamage)
+	damage += game.config.AsteroidMinDamage
+	return damage
+}
+
+// Longer words come on bigger asteroids, up to AsteroidMaxSize.
+func (game *Game) asteroidSize(word string) float32 {
+	size := game.config.AsteroidSize + game.config.AsteroidSizePerLetter*float32(utf8.RuneCountInString(word))
+	if size > game.config.AsteroidMaxSize {
+		size = game.config.AsteroidMaxSize
+	}
+	return size
+}
+
+func reachedLevel(level int, fromLevel int) bool {
+	return fromLevel > 0 && level >= fromLevel
+}
+
+// movements returns the kinds of movement asteroids may have on the
+// current level.
+func (game *Game) movements() []Movement {
+	movements := []Movement{MovementStraight}
+	if reachedLevel(game.level, game.config.DiagonalFromLevel) {
+		movements = append(movements, MovementDiagonal)
+	}
+	if reachedLevel(game.level, game.config.WobbleFromLevel) {
+		movements = append(movements, MovementWobble)
+	}
+	if reachedLevel(game.level, game.config.AcceleratingFromLevel) {
+		movements = append(movements, MovementAccelerating)
+	}
+	if reachedLevel(game.level, game.config.HomingFromLevel) {
+		movements = append(movements, MovementHoming)
+	}
+	return movements
+}
+
+// spawnPosition picks where the next asteroid enters the screen and the
+// heading it enters with. Most come from the top. From SideSpawnFromLevel
+// some come in from the left or right, heading for a random point at the
+// bottom of the screen.
+func (game *Game) spawnPosition(size float32) (float32, float32, float32, float32) {
+	spawnWidth := game.config.Width - game.config.AsteroidSpawnMarginLeft - game.config.AsteroidSpawnMarginRight
+	x := float32(game.random.Intn(int(spawnWidth))) + game.config.AsteroidSpawnMarginLeft
+	if !reachedLevel(game.level, game.config.SideSpawnFromLevel) ||
+		game.random.Float32() >= game.config.SideSpawnChance {
+		return x, game.config.StartAsteroidY, 0.0, 1.0
+	}
+	y := game.random.Float32() * game.config.Height / 3
+	startX := -size / 2
+	if game.random.Intn(2) == 1 {
+		startX = game.config.Width + size/2
+	}
+	return startX, y, x - startX, game.config.Height - y
+}
+
+func (game *Game) trajectory(movement Movement, headingX, headingY float32) Trajectory {
+	trajectory := Trajectory{Movement: movement}
+	switch movement {
+	case MovementDiagonal:
+		angle := (game.random.Float64()*2.0 - 1.0) * float64(game.config.DiagonalMaxAngle) * math.Pi / 180.0
+		sin, cos := math.Sincos(angle)
+		headingX, headingY = headingX*float32(cos)-headingY*float32(sin),
+			headingX*float32(sin)+headingY*float32(cos)
+	case MovementWobble:
+		trajectory.WobbleAmplitude = game.config.WobbleAmplitude
+		trajectory.WobblePeriod = game.config.WobblePeriod
+	case MovementAccelerating:
+		trajectory.Acceleration = game.config.AsteroidAcceleration
+	case MovementHoming:
+		trajectory.TurnRate = game.config.HomingTurnRate
+		trajectory.TargetX = game.config.ShipX
+		trajectory.TargetY = game.config.ShipY
+	}
+	trajectory.setHeading(headingX, headingY)
+	return trajectory
+}
+
+// addAsteroid numbers the asteroid and puts it in play.
+func (game *Game) addAsteroid(asteroid *Asteroid) {
+	game.asteroidsSpawned++
+	asteroid.id = game.asteroidsSpawned
+	game.asteroids = append(game.asteroids, asteroid)
+}
+
+func (game *Game) spawnNextAsteroid() {
+	if game.IsBossLevel() {
+		game.spawnBoss()
+		return
+	}
+	variant := game.random.Intn(game.config.AsteroidVariants)
+	kind := game.asteroidKind()
+	velocity := game.asteroidVelocity
+	var word string
+	if kind == AsteroidFast {
+		word = game.words.ShortWord(game.random, game.config.FastMaxWordLength)
+		velocity *= game.config.FastVelocityMultiplier
+	} else {
+		word = game.words.RandomWord(game.random, game.level)
+	}
+	size := game.asteroidSize(word)
+	x, y, headingX, headingY := game.spawnPosition(size)
+	movements := game.movements()
+	movement := movements[game.random.Intn(len(movements))]
+	asteroid := NewAsteroid(x, y, size, velocity,
+		game.trajectory(movement, headingX, headingY), variant, word)
+	asteroid.kind = kind
+	if kind == AsteroidArmored {
+		for i := 1; i < game.config.ArmoredWords; i++ {
+			asteroid.armor = append(asteroid.armor, game.words.RandomWord(game.random, game.level))
+		}
+	}
+	game.addAsteroid(asteroid)
+	game.asteroidsLeftToSpawn--
+}
+
+// startWave sets up the asteroids of the level. A boss level has only the
+// boss, and the level ends once it and everything it shed are gone.
+func (game *Game) startWave() {
+	game.boss = nil
+	if game.IsBossLevel() {
+		game.asteroidsLeftToSpawn = 1
+	}
+}
+
+func (game *Game) goToNextLevel() {
+	game.asteroids = make([]*Asteroid, 0)
+	game.level++
+	game.numberOfAsteroidsToSpawn += game.config.AsteroidsToSpawnIncrement
+	game.asteroidsLeftToSpawn = game.numberOfAsteroidsToSpawn
+	game.delayBetweenAsteroids += game.config.DelayBetweenAsteroidsIncrement
+	if game.delayBetweenAsteroids < game.config.MinDelayBetweenAsteroids {
+		game.delayBetweenAsteroids = game.config.MinDelayBetweenAsteroids
+	}
+	game.timeUntilNextAsteroidSpawn = game.delayBetweenAsteroids
+	game.asteroidVelocity += game.config.AsteroidVelocityIncrement
+	game.startWave()
+	if game.callbacks.NextLevel != nil {
+		game.callbacks.NextLevel(game.level)
+	}
+}
+
+func (game *Game) asteroidNotDestroyed(asteroid *Asteroid) {
+	if asteroid == game.target {
+		game.target = nil
+		game.input = ""
+		game.wordMistyped = false
+	}
+	damage := 0
+	switch asteroid.kind {
+	case AsteroidBoss:
+		damage = game.config.BossDamage
+	case AsteroidSupply:
+		damage = 0
+	default:
+		damage = game.asteroidDamage()
+	}
+	game.player.TakeDamage(damage)
+	if game.callbacks.AsteroidNotDestroyed != nil {
+		game.callbacks.AsteroidNotDestroyed(asteroid, damage)
+	}
+	game.checkGameOver()
+}
+
+func (game *Game) checkGameOver() {
+	if game.player.IsDead() {
+		game.over = true
+		if game.callbacks.GameOver != nil {
+			game.callbacks.GameOver()
+		}
+	}
+}
+
+func (game *Game) Update(deltaTime float32) {
+	if game.over {
+		return
+	}
+	game.time += deltaTime
+	if game.lockout > 0.0 {
+		game.lockout -= deltaTime
+	}
+	game.stats.SetDuration(game.time)
+
+	if game.asteroidsLeftToSpawn > 0 {
+		game.timeUntilNextAsteroidSpawn -= deltaTime
+		if game.timeUntilNextAsteroidSpawn <= 0.0 {
+			game.spawnNextAsteroid()
+
+			var leftOverTime float32 = 0.0
+			if game.timeUntilNextAsteroidSpawn < 0.0 {
+				leftOverTime = game.timeUntilNextAsteroidSpawn
+			}
+			game.timeUntilNextAsteroidSpawn = game.delayBetweenAsteroids + leftOverTime
+		}
+	}
+
+	game.shed(deltaTime)
+
+	allAsteroidsDead := true
+	for _, asteroid := range game.asteroids {
+		if asteroid.IsAlive() {
+			asteroid.Update(deltaTime, game.config.Width, game.config.Height)
+			if asteroid.IsAlive() {
+				allAsteroidsDead = false
+			} else {
+				game.asteroidNotDestroyed(asteroid)
+				if game.over {
+					return
+				}
+			}
+		}
+	}
+
+	if allAsteroidsDead && game.asteroidsLeftToSpawn <= 0 {
+		game.goToNextLevel()
+	}
+}
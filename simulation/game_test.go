@@ -0,0 +1,229 @@
+package simulation
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var testWords = []string{
+	"at", "it", "on", "the", "sun", "ice", "star", "rock", "moon", "ship",
+	"orbit", "comet", "laser", "planet", "rocket", "meteor", "galaxy",
+	"gravity", "journey", "quantum", "asteroid", "universe", "spaceship",
+	"zephyrous", "satellite", "quizzically", "extraordinary", "juxtaposition",
+}
+
+// testConfig is the default configuration with only plain asteroids, so
+// that every asteroid is destroyed by typing its word once.
+func testConfig() Config {
+	config := DefaultConfig()
+	config.SplitterWeight = 0
+	config.ArmoredWeight = 0
+	config.FastWeight = 0
+	config.SupplyWeight = 0
+	config.BossEveryLevels = 0
+	return config
+}
+
+func testWordPack() *WordPack {
+	return &WordPack{Name: "test", Words: append([]string{}, testWords...)}
+}
+
+func startTestGame(config Config, seed int64, callbacks Callbacks) *Game {
+	game := NewGame(config)
+	game.Start(seed, testWordPack(), callbacks)
+	return game
+}
+
+// averageDifficulty draws words for the level and returns how hard they
+// are on average.
+func averageDifficulty(pack *WordPack, level int) float64 {
+	layout := FindKeyboardLayout(DefaultKeyboardLayout)
+	pack.rankWords(layout)
+	random := rand.New(rand.NewSource(1))
+	difficulty := 0.0
+	for i := 0; i < 200; i++ {
+		difficulty += wordDifficulty(layout, pack.RandomWord(random, level))
+	}
+	return difficulty / 200.0
+}
+
+// typeWord types the word of the asteroid without a mistake and returns it.
+func typeWord(t *testing.T, game *Game, asteroid *Asteroid) string {
+	if !game.Type([]rune(asteroid.Word())[0]) {
+		t.Fatalf("could not start typing %q", asteroid.Word())
+	}
+	word := []rune(game.Target().Word())
+	for game.Target() != nil && game.Input() != "" {
+		if !game.Type(word[len([]rune(game.Input()))]) {
+			t.Fatalf("could not type %q after %q", string(word), game.Input())
+		}
+	}
+	return string(word)
+}
+
+func firstAlive(game *Game) *Asteroid {
+	for _, asteroid := range game.Asteroids() {
+		if asteroid.IsAlive() {
+			return asteroid
+		}
+	}
+	return nil
+}
+
+// typeWords plays like a player who never makes a mistake, typing the word
+// of every asteroid that is alive.
+func typeWords(t *testing.T, game *Game) {
+	for !game.IsOver() && firstAlive(game) != nil {
+		typeWord(t, game, firstAlive(game))
+	}
+}
+
+func TestLevelProgression(t *testing.T) {
+	config := testConfig()
+	levels := []int{}
+	destroyed := 0
+	destroyedPerLevel := []int{}
+	game := startTestGame(config, 1, Callbacks{
+		AsteroidDestroyed: func(asteroid *Asteroid) {
+			destroyed++
+		},
+		NextLevel: func(level int) {
+			levels = append(levels, level)
+			destroyedPerLevel = append(destroyedPerLevel, destroyed)
+			destroyed = 0
+		},
+	})
+	for step := 0; step < 100000 && game.Level() < 4; step++ {
+		game.Update(10.0)
+		typeWords(t, game)
+	}
+
+	if !reflect.DeepEqual(levels, []int{2, 3, 4}) {
+		t.Fatalf("got levels %v, want 2, 3 and 4", levels)
+	}
+	for i, count := range destroyedPerLevel {
+		want := config.StartNumberOfAsteroids + i*config.AsteroidsToSpawnIncrement
+		if count != want {
+			t.Errorf("got %d asteroids on level %d, want %d", count, i+1, want)
+		}
+	}
+	if game.Player().CurrentHealth() != config.PlayerStartHealth {
+		t.Errorf("got health %d, want %d without missed asteroids", game.Player().CurrentHealth(), config.PlayerStartHealth)
+	}
+}
+
+func TestScore(t *testing.T) {
+	config := testConfig()
+	game := startTestGame(config, 1, Callbacks{})
+	game.Update(config.StartDelayBetweenAsteroids)
+	if len(game.Asteroids()) != 1 {
+		t.Fatalf("got %d asteroids after the first delay, want 1", len(game.Asteroids()))
+	}
+	word := typeWord(t, game, game.Asteroids()[0])
+	if game.Score() != len(word)*10 {
+		t.Errorf("got score %d for %q on level 1, want %d", game.Score(), word, len(word)*10)
+	}
+}
+
+func TestCombo(t *testing.T) {
+	config := testConfig()
+	config.StartDelayBetweenAsteroids = 1.0
+	config.ComboWordsPerMultiplier = 2
+	config.ComboMaxMultiplier = 2
+	game := startTestGame(config, 1, Callbacks{})
+	for len(game.Asteroids()) < config.StartNumberOfAsteroids {
+		game.Update(1.0)
+	}
+
+	for i, multiplier := range []int{1, 2, 2, 2} {
+		score := game.Score()
+		word := typeWord(t, game, firstAlive(game))
+		if game.Multiplier() != multiplier {
+			t.Errorf("got multiplier %d after %d words, want %d", game.Multiplier(), i+1, multiplier)
+		}
+		if game.Score()-score != len(word)*10*multiplier {
+			t.Errorf("got %d points for %q, want %d", game.Score()-score, word, len(word)*10*multiplier)
+		}
+	}
+
+	game.Type('#')
+	if game.Combo() != 0 || game.Multiplier() != 1 {
+		t.Errorf("got combo %d and multiplier %d after a mistype, want 0 and 1", game.Combo(), game.Multiplier())
+	}
+}
+
+func TestGameOver(t *testing.T) {
+	config := testConfig()
+	damage := 0
+	gameOver := 0
+	game := startTestGame(config, 1, Callbacks{
+		AsteroidNotDestroyed: func(asteroid *Asteroid, amount int) {
+			if amount < config.AsteroidMinDamage || amount >= config.AsteroidMaxDamage {
+				t.Errorf("got damage %d, want it from %d up to %d", amount, config.AsteroidMinDamage, config.AsteroidMaxDamage)
+			}
+			damage += amount
+		},
+		GameOver: func() {
+			gameOver++
+		},
+	})
+	for step := 0; step < 100000 && !game.IsOver(); step++ {
+		game.Update(10.0)
+	}
+	game.Update(10.0)
+
+	if !game.IsOver() || gameOver != 1 {
+		t.Fatalf("got game over %v reported %d times, want it reported once", game.IsOver(), gameOver)
+	}
+	if !game.Player().IsDead() || damage < config.PlayerStartHealth {
+		t.Errorf("got %d damage and a dead player %v, want at least %d", damage, game.Player().IsDead(), config.PlayerStartHealth)
+	}
+}
+
+type miss struct {
+	word   string
+	x      float32
+	damage int
+}
+
+// record plays a game in which no word is typed and returns every asteroid
+// that reached Earth with the damage it did.
+func record(seed int64) []miss {
+	misses := []miss{}
+	game := startTestGame(DefaultConfig(), seed, Callbacks{
+		AsteroidNotDestroyed: func(asteroid *Asteroid, damage int) {
+			misses = append(misses, miss{asteroid.Word(), asteroid.X(), damage})
+		},
+	})
+	for step := 0; step < 100000 && !game.IsOver(); step++ {
+		game.Update(10.0)
+	}
+	return misses
+}
+
+func TestSameSeed(t *testing.T) {
+	misses := record(42)
+	if len(misses) == 0 {
+		t.Fatal("no asteroid reached Earth")
+	}
+	if !reflect.DeepEqual(record(42), misses) {
+		t.Errorf("two games with the same seed differ")
+	}
+	if reflect.DeepEqual(record(43), misses) {
+		t.Errorf("games with different seeds are the same")
+	}
+}
+
+// The words are harder the higher the level.
+func TestDifficultyCurve(t *testing.T) {
+	pack := testWordPack()
+	previous := 0.0
+	for _, level := range []int{1, 4, 8, 12, 16} {
+		difficulty := averageDifficulty(pack, level)
+		if difficulty <= previous {
+			t.Errorf("got average difficulty %v on level %d, want more than %v", difficulty, level, previous)
+		}
+		previous = difficulty
+	}
+}
@@ -0,0 +1,104 @@
+package simulation
+
+import (
+	"math"
+)
+
+type AsteroidKind int
+
+const (
+	AsteroidNormal AsteroidKind = iota
+	AsteroidSplitter
+	AsteroidArmored
+	AsteroidFast
+	AsteroidSupply
+	AsteroidBoss
+)
+
+func (kind AsteroidKind) String() string {
+	switch kind {
+	case AsteroidSplitter:
+		return "splitter"
+	case AsteroidArmored:
+		return "armored"
+	case AsteroidFast:
+		return "fast"
+	case AsteroidSupply:
+		return "supply"
+	case AsteroidBoss:
+		return "boss"
+	}
+	return "normal"
+}
+
+// asteroidKind picks the kind of the next asteroid, weighted by how often
+// each kind that has been reached by the current level should appear.
+func (game *Game) asteroidKind() AsteroidKind {
+	kinds := []struct {
+		kind      AsteroidKind
+		weight    int
+		fromLevel int
+	}{
+		{AsteroidNormal, game.config.NormalWeight, 1},
+		{AsteroidSplitter, game.config.SplitterWeight, game.config.SplitterFromLevel},
+		{AsteroidArmored, game.config.ArmoredWeight, game.config.ArmoredFromLevel},
+		{AsteroidFast, game.config.FastWeight, game.config.FastFromLevel},
+		{AsteroidSupply, game.config.SupplyWeight, game.config.SupplyFromLevel},
+	}
+	total := 0
+	for _, kind := range kinds {
+		if reachedLevel(game.level, kind.fromLevel) {
+			total += kind.weight
+		}
+	}
+	if total == 0 {
+		return AsteroidNormal
+	}
+	pick := game.random.Intn(total)
+	for _, kind := range kinds {
+		if !reachedLevel(game.level, kind.fromLevel) {
+			continue
+		}
+		if pick < kind.weight {
+			return kind.kind
+		}
+		pick -= kind.weight
+	}
+	return AsteroidNormal
+}
+
+func (game *Game) kindScore(kind AsteroidKind) int {
+	switch kind {
+	case AsteroidSplitter:
+		return game.config.SplitterScore
+	case AsteroidArmored:
+		return game.config.ArmoredScore
+	case AsteroidFast:
+		return game.config.FastScore
+	case AsteroidSupply:
+		return game.config.SupplyScore
+	case AsteroidBoss:
+		return game.config.BossScore
+	}
+	return game.config.NormalScore
+}
+
+// splitAsteroid breaks a destroyed splitter into fragments with short
+// words, which fly apart from where it was.
+func (game *Game) splitAsteroid(asteroid *Asteroid) {
+	fragments := game.config.SplitterFragments
+	for i := 0; i < fragments; i++ {
+		word := game.words.ShortWord(game.random, game.config.FragmentMaxWordLength)
+		angle := 0.0
+		if fragments > 1 {
+			spread := float64(game.config.DiagonalMaxAngle) * math.Pi / 180.0
+			angle = -spread + 2.0*spread*float64(i)/float64(fragments-1)
+		}
+		sin, cos := math.Sincos(angle)
+		trajectory := StraightDown()
+		trajectory.setHeading(-float32(sin), float32(cos))
+		fragment := NewAsteroid(asteroid.x, asteroid.y, game.config.AsteroidSize,
+			asteroid.velocity, trajectory, asteroid.variant, word)
+		game.addAsteroid(fragment)
+	}
+}
@@ -0,0 +1,303 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"unicode"
+)
+
+var (
+	DefaultKeyboardLayout string = "QWERTY"
+
+	// KeyboardLayouts holds the presets, followed by the layouts loaded
+	// with AddKeyboardLayout.
+	KeyboardLayouts []*KeyboardLayout = []*KeyboardLayout{
+		mustKeyboardLayout(KeyboardLayoutDefinition{
+			Name: "QWERTY",
+			Rows: []KeyRow{
+				{Keys: "`1234567890-=", Shifted: "~!@#$%^&*()_+", Offset: 0.0},
+				{Keys: "qwertyuiop[]\\", Shifted: "QWERTYUIOP{}|", Offset: 1.5},
+				{Keys: "asdfghjkl;'", Shifted: "ASDFGHJKL:\"", Offset: 1.75},
+				{Keys: "zxcvbnm,./", Shifted: "ZXCVBNM<>?", Offset: 2.25},
+			},
+		}),
+		mustKeyboardLayout(KeyboardLayoutDefinition{
+			Name: "Dvorak",
+			Rows: []KeyRow{
+				{Keys: "`1234567890[]", Shifted: "~!@#$%^&*(){}", Offset: 0.0},
+				{Keys: "',.pyfgcrl/=\\", Shifted: "\"<>PYFGCRL?+|", Offset: 1.5},
+				{Keys: "aoeuidhtns-", Shifted: "AOEUIDHTNS_", Offset: 1.75},
+				{Keys: ";qjkxbmwvz", Shifted: ":QJKXBMWVZ", Offset: 2.25},
+			},
+		}),
+		mustKeyboardLayout(KeyboardLayoutDefinition{
+			Name: "Colemak",
+			Rows: []KeyRow{
+				{Keys: "`1234567890-=", Shifted: "~!@#$%^&*()_+", Offset: 0.0},
+				{Keys: "qwfpgjluy;[]\\", Shifted: "QWFPGJLUY:{}|", Offset: 1.5},
+				{Keys: "arstdhneio'", Shifted: "ARSTDHNEIO\"", Offset: 1.75},
+				{Keys: "zxcvbkm,./", Shifted: "ZXCVBKM<>?", Offset: 2.25},
+			},
+		}),
+		mustKeyboardLayout(KeyboardLayoutDefinition{
+			Name: "AZERTY",
+			Rows: []KeyRow{
+				{Keys: "²&é\"'(-è_çà)=", Shifted: " 1234567890°+", Offset: 0.0},
+				{Keys: "azertyuiop^$", Shifted: "AZERTYUIOP¨£", Offset: 1.5},
+				{Keys: "qsdfghjklmù*", Shifted: "QSDFGHJKLM%µ", Offset: 1.75},
+				{Keys: "<wxcvbn,;:!", Shifted: ">WXCVBN?./§", Offset: 1.25, Fingers: "00123366789"},
+			},
+		}),
+	}
+
+	// numberRowFingers and columnFingers map a column of the number row and
+	// of the letter rows to the finger that types it, for rows that do not
+	// list their fingers. Columns past the end belong to the right little
+	// finger.
+	numberRowFingers []int = []int{0, 0, 1, 2, 3, 3, 6, 6, 7, 8, 9}
+	columnFingers    []int = []int{0, 1, 2, 3, 3, 6, 6, 7, 8, 9}
+
+	spaceFinger int     = 5
+	spaceOffset float32 = 3.75
+	spaceWidth  float32 = 6.25
+)
+
+// KeyboardLayoutDefinition describes a layout as read from a JSON file in
+// resources/layouts. Rows go from the number row down to the bottom row
+// of letters, and each key is one key wide.
+type KeyboardLayoutDefinition struct {
+	Name string   `json:"name"`
+	Rows []KeyRow `json:"rows"`
+}
+
+// KeyRow lists the characters of a row of keys from left to right, with
+// the character typed with shift on the same key at the same position of
+// Shifted, or a space for none. Offset is where the row starts, in key
+// widths from the left edge of the keyboard. Fingers optionally lists
+// the finger of every key as a digit.
+type KeyRow struct {
+	Keys    string  `json:"keys"`
+	Shifted string  `json:"shifted"`
+	Offset  float32 `json:"offset"`
+	Fingers string  `json:"fingers,omitempty"`
+}
+
+// Key is a physical key. Its finger is counted as in stats.FingerNames,
+// from the left little finger at 0 to the right little finger at 9, with
+// the thumbs only pressing the space bar. X and Width are measured in key
+// widths from the left edge of the keyboard.
+type Key struct {
+	Character rune
+	Shifted   rune
+	Row       int
+	Column    int
+	X         float32
+	Width     float32
+	Finger    int
+}
+
+type KeyboardLayout struct {
+	Name string
+	Keys []*Key
+	Rows int
+	keys map[rune]*Key
+}
+
+func NewKeyboardLayout(definition KeyboardLayoutDefinition) (*KeyboardLayout, error) {
+	if definition.Name == "" {
+		return nil, fmt.Errorf("keyboard layout has no name")
+	}
+	if len(definition.Rows) == 0 {
+		return nil, fmt.Errorf("keyboard layout %s has no rows", definition.Name)
+	}
+	layout := &KeyboardLayout{}
+	layout.Name = definition.Name
+	layout.Keys = make([]*Key, 0)
+	layout.Rows = len(definition.Rows) + 1
+	layout.keys = make(map[rune]*Key)
+	for row, keyRow := range definition.Rows {
+		characters := []rune(keyRow.Keys)
+		shifted := []rune(keyRow.Shifted)
+		fingers := []rune(keyRow.Fingers)
+		if len(shifted) != len(characters) {
+			return nil, fmt.Errorf("keyboard layout %s: row %d has %d keys but %d shifted characters",
+				definition.Name, row+1, len(characters), len(shifted))
+		}
+		if len(fingers) != 0 && len(fingers) != len(characters) {
+			return nil, fmt.Errorf("keyboard layout %s: row %d has %d keys but %d fingers",
+				definition.Name, row+1, len(characters), len(fingers))
+		}
+		for column, character := range characters {
+			key := &Key{
+				Character: character,
+				Row:       row,
+				Column:    column,
+				X:         keyRow.Offset + float32(column),
+				Width:     1.0,
+				Finger:    defaultFinger(row, column),
+			}
+			if shifted[column] != ' ' {
+				key.Shifted = shifted[column]
+			}
+			if len(fingers) != 0 {
+				key.Finger = int(fingers[column] - '0')
+				if key.Finger < 0 || key.Finger > 9 {
+					return nil, fmt.Errorf("keyboard layout %s: row %d has finger %q, expected 0-9",
+						definition.Name, row+1, fingers[column])
+				}
+			}
+			err := layout.add(key)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	space := &Key{
+		Character: ' ',
+		Row:       len(definition.Rows),
+		X:         spaceOffset,
+		Width:     spaceWidth,
+		Finger:    spaceFinger,
+	}
+	err := layout.add(space)
+	if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+func mustKeyboardLayout(definition KeyboardLayoutDefinition) *KeyboardLayout {
+	layout, err := NewKeyboardLayout(definition)
+	if err != nil {
+		panic(err)
+	}
+	return layout
+}
+
+func defaultFinger(row, column int) int {
+	fingers := columnFingers
+	if row == 0 {
+		fingers = numberRowFingers
+	}
+	if column < len(fingers) {
+		return fingers[column]
+	}
+	return fingers[len(fingers)-1]
+}
+
+func (layout *KeyboardLayout) add(key *Key) error {
+	for _, character := range []rune{key.Character, key.Shifted} {
+		if character == 0 {
+			continue
+		}
+		if _, ok := layout.keys[character]; ok {
+			return fmt.Errorf("keyboard layout %s: %q is on more than one key", layout.Name, character)
+		}
+		layout.keys[character] = key
+	}
+	layout.Keys = append(layout.Keys, key)
+	return nil
+}
+
+// Key returns the key that types the character and whether shift has to
+// be held for it, or nil when the layout has no such key. Capitals that
+// are not on the layout, such as in other alphabets, are looked up as
+// their lowercase letter with shift.
+func (layout *KeyboardLayout) Key(character rune) (*Key, bool) {
+	key, ok := layout.keys[character]
+	if ok {
+		return key, character == key.Shifted
+	}
+	key, ok = layout.keys[unicode.ToLower(character)]
+	if ok && unicode.IsUpper(character) {
+		return key, true
+	}
+	return nil, false
+}
+
+// Finger and Row return -1 for characters that are not on the layout.
+func (layout *KeyboardLayout) Finger(character rune) int {
+	key, _ := layout.Key(character)
+	if key == nil {
+		return -1
+	}
+	return key.Finger
+}
+
+func (layout *KeyboardLayout) Row(character rune) int {
+	key, _ := layout.Key(character)
+	if key == nil {
+		return -1
+	}
+	return key.Row
+}
+
+// Hand returns 0 for a character typed with the left hand and 1 for the
+// right hand, or -1 when the character is not on the layout.
+func (layout *KeyboardLayout) Hand(character rune) int {
+	finger := layout.Finger(character)
+	if finger < 0 {
+		return -1
+	}
+	if finger < 5 {
+		return 0
+	}
+	return 1
+}
+
+func (layout *KeyboardLayout) hasKey(character rune) bool {
+	key, _ := layout.Key(character)
+	return key != nil
+}
+
+func FindKeyboardLayout(name string) *KeyboardLayout {
+	for _, layout := range KeyboardLayouts {
+		if layout.Name == name {
+			return layout
+		}
+	}
+	return nil
+}
+
+// AddKeyboardLayout makes the layout available next to the presets.
+func AddKeyboardLayout(layout *KeyboardLayout) error {
+	if FindKeyboardLayout(layout.Name) != nil {
+		return fmt.Errorf("keyboard layout %s is defined more than once", layout.Name)
+	}
+	KeyboardLayouts = append(KeyboardLayouts, layout)
+	return nil
+}
+
+// LoadKeyboardLayouts loads every layout definition in the directory of
+// files.
+func LoadKeyboardLayouts(files fs.FS, directory string) ([]*KeyboardLayout, error) {
+	entries, err := fs.ReadDir(files, directory)
+	if err != nil {
+		return nil, err
+	}
+	layouts := make([]*KeyboardLayout, 0)
+	for _, file := range entries {
+		if file.IsDir() || strings.ToLower(path.Ext(file.Name())) != ".json" {
+			continue
+		}
+		layoutPath := path.Join(directory, file.Name())
+		data, err := fs.ReadFile(files, layoutPath)
+		if err != nil {
+			return nil, err
+		}
+		definition := KeyboardLayoutDefinition{}
+		err = json.Unmarshal(data, &definition)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", layoutPath, err)
+		}
+		layout, err := NewKeyboardLayout(definition)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", layoutPath, err)
+		}
+		layouts = append(layouts, layout)
+	}
+	return layouts, nil
+}
@@ -0,0 +1,39 @@
+package simulation
+
+import "testing"
+
+func TestHand(t *testing.T) {
+	layout := FindKeyboardLayout("QWERTY")
+	hands := map[rune]int{'a': 0, 'T': 0, 'j': 1, '?': 1, 'я': -1, 'Я': -1}
+	for character, hand := range hands {
+		if layout.Hand(character) != hand {
+			t.Errorf("got hand %d for %q, want %d", layout.Hand(character), character, hand)
+		}
+	}
+	if layout.Finger('я') != -1 || layout.Row('я') != -1 {
+		t.Errorf("got finger %d and row %d for an off-layout character, want -1",
+			layout.Finger('я'), layout.Row('я'))
+	}
+}
+
+// Characters that are not on the layout are not typed with either hand, so
+// they never count as typed with the same hand as their neighbours.
+func TestWordDifficultyOffLayout(t *testing.T) {
+	layout := FindKeyboardLayout("QWERTY")
+	for _, word := range []string{"жук", "aя", "яa"} {
+		want := 0.0
+		for _, letter := range word {
+			want += wordDifficultyLengthWeight + letterRarity(letter)*wordDifficultyRareLetterWeight
+		}
+		if wordDifficulty(layout, word) != want {
+			t.Errorf("got difficulty %v for %q, want %v", wordDifficulty(layout, word), word, want)
+		}
+	}
+
+	// Both letters are typed with the left hand.
+	want := 2*wordDifficultyLengthWeight + (letterRarity('a')+letterRarity('s'))*wordDifficultyRareLetterWeight +
+		wordDifficultySameHandWeight
+	if wordDifficulty(layout, "as") != want {
+		t.Errorf("got difficulty %v for %q, want %v", wordDifficulty(layout, "as"), "as", want)
+	}
+}
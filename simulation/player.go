@@ -0,0 +1,40 @@
+package simulation
+
+type Player struct {
+	startHealth int
+	health      int
+}
+
+func NewPlayer(startHealth int) *Player {
+	player := &Player{}
+	player.startHealth = startHealth
+	player.health = startHealth
+	return player
+}
+
+func (player *Player) Reset() {
+	player.health = player.startHealth
+}
+
+func (player *Player) TakeDamage(damage int) {
+	player.health -= damage
+	if player.health < 0 {
+		player.health = 0
+	}
+}
+
+// Heal restores health, but never beyond what the player started with.
+func (player *Player) Heal(health int) {
+	player.health += health
+	if player.health > player.startHealth {
+		player.health = player.startHealth
+	}
+}
+
+func (player *Player) CurrentHealth() int {
+	return player.health
+}
+
+func (player *Player) IsDead() bool {
+	return player.health == 0
+}
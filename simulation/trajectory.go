@@ -0,0 +1,91 @@
+package simulation
+
+import (
+	"math"
+)
+
+type Movement int
+
+const (
+	MovementStraight Movement = iota
+	MovementDiagonal
+	MovementWobble
+	MovementAccelerating
+	MovementHoming
+)
+
+func (movement Movement) String() string {
+	switch movement {
+	case MovementDiagonal:
+		return "diagonal"
+	case MovementWobble:
+		return "wobble"
+	case MovementAccelerating:
+		return "accelerating"
+	case MovementHoming:
+		return "homing"
+	}
+	return "straight"
+}
+
+// Trajectory is how an asteroid moves. Every asteroid travels along its
+// heading, which is a unit vector. Wobbling asteroids swing from side to
+// side across it, accelerating ones speed up and homing ones turn their
+// heading toward the ship until they have passed it.
+type Trajectory struct {
+	Movement        Movement
+	HeadingX        float32
+	HeadingY        float32
+	Acceleration    float32
+	WobbleAmplitude float32
+	WobblePeriod    float32
+	TurnRate        float32
+	TargetX         float32
+	TargetY         float32
+}
+
+// StraightDown is the trajectory of an asteroid that falls without
+// changing direction or speed.
+func StraightDown() Trajectory {
+	return Trajectory{Movement: MovementStraight, HeadingX: 0.0, HeadingY: 1.0}
+}
+
+func (trajectory *Trajectory) setHeading(x, y float32) {
+	length := float32(math.Hypot(float64(x), float64(y)))
+	if length == 0.0 {
+		trajectory.HeadingX, trajectory.HeadingY = 0.0, 1.0
+		return
+	}
+	trajectory.HeadingX = x / length
+	trajectory.HeadingY = y / length
+}
+
+// wobble returns how far the asteroid is from its heading after age
+// milliseconds.
+func (trajectory *Trajectory) wobble(age float32) float32 {
+	if trajectory.Movement != MovementWobble || trajectory.WobblePeriod <= 0.0 {
+		return 0.0
+	}
+	phase := 2.0 * math.Pi * float64(age/trajectory.WobblePeriod)
+	return trajectory.WobbleAmplitude * float32(math.Sin(phase))
+}
+
+// steer turns the heading toward the target by at most TurnRate degrees
+// per millisecond.
+func (trajectory *Trajectory) steer(x, y, deltaTime float32) {
+	if trajectory.Movement != MovementHoming || y >= trajectory.TargetY {
+		return
+	}
+	heading := math.Atan2(float64(trajectory.HeadingY), float64(trajectory.HeadingX))
+	wanted := math.Atan2(float64(trajectory.TargetY-y), float64(trajectory.TargetX-x))
+	turn := math.Remainder(wanted-heading, 2.0*math.Pi)
+	maxTurn := float64(trajectory.TurnRate*deltaTime) * math.Pi / 180.0
+	if turn > maxTurn {
+		turn = maxTurn
+	} else if turn < -maxTurn {
+		turn = -maxTurn
+	}
+	heading += turn
+	trajectory.HeadingX = float32(math.Cos(heading))
+	trajectory.HeadingY = float32(math.Sin(heading))
+}
@@ -0,0 +1,224 @@
+package simulation
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	LowercaseCharacters string = "lowercase"
+	PrintableCharacters string = "printable"
+	UnicodeCharacters   string = "unicode"
+)
+
+type WordPack struct {
+	Name       string   `json:"name"`
+	Language   string   `json:"language"`
+	Difficulty string   `json:"difficulty"`
+	Characters string   `json:"characters"`
+	Words      []string `json:"words"`
+	Sentences  []string `json:"sentences"`
+
+	path         string
+	difficulties []float64
+	layout       *KeyboardLayout
+}
+
+// Checksum identifies the contents of the pack, so that a replay is only
+// played back with the words it was recorded with. It does not depend on
+// the order of the words, which are sorted by difficulty once loaded.
+func (pack *WordPack) Checksum() string {
+	words := append([]string{}, pack.Words...)
+	sentences := append([]string{}, pack.Sentences...)
+	sort.Strings(words)
+	sort.Strings(sentences)
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00", pack.Characters)
+	for _, word := range words {
+		fmt.Fprintf(hash, "%s\x00", word)
+	}
+	for _, sentence := range sentences {
+		fmt.Fprintf(hash, "\x01%s\x00", sentence)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (pack *WordPack) Description() string {
+	details := make([]string, 0, 2)
+	if pack.Language != "" {
+		details = append(details, pack.Language)
+	}
+	if pack.Difficulty != "" {
+		details = append(details, pack.Difficulty)
+	}
+	if len(details) == 0 {
+		return pack.Name
+	}
+	return fmt.Sprintf("%s (%s)", pack.Name, strings.Join(details, ", "))
+}
+
+// typeable reports whether the character can be used in the words of the
+// pack. Packs only contain the letters a-z, unless they opt into every
+// printable ASCII character, which includes capitals, digits, punctuation
+// and spaces, or into every printable Unicode character for other
+// alphabets and scripts.
+func (pack *WordPack) typeable(character rune) bool {
+	switch pack.Characters {
+	case PrintableCharacters:
+		return character >= ' ' && character <= '~'
+	case UnicodeCharacters:
+		return unicode.IsPrint(character)
+	}
+	return character >= 'a' && character <= 'z'
+}
+
+func (pack *WordPack) validate() error {
+	switch pack.Characters {
+	case "":
+		pack.Characters = LowercaseCharacters
+	case LowercaseCharacters, PrintableCharacters, UnicodeCharacters:
+	default:
+		return fmt.Errorf("%s: unknown characters %q, expected %q, %q or %q",
+			pack.path, pack.Characters, LowercaseCharacters, PrintableCharacters, UnicodeCharacters)
+	}
+	if len(pack.Words) == 0 {
+		return fmt.Errorf("%s: word pack contains no words", pack.path)
+	}
+	for _, word := range pack.Words {
+		if word == "" || strings.TrimSpace(word) != word {
+			return fmt.Errorf("%s: word %q is empty or starts or ends with a space", pack.path, word)
+		}
+		for _, character := range word {
+			if !pack.typeable(character) {
+				return fmt.Errorf("%s: word %q contains %q, which cannot be typed with %s characters",
+					pack.path, word, character, pack.Characters)
+			}
+		}
+	}
+	for _, sentence := range pack.Sentences {
+		if strings.TrimSpace(sentence) == "" {
+			return fmt.Errorf("%s: sentence is empty", pack.path)
+		}
+		for _, character := range sentence {
+			if !pack.typeable(character) && character != ' ' {
+				return fmt.Errorf("%s: sentence %q contains %q, which cannot be typed with %s characters",
+					pack.path, sentence, character, pack.Characters)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadWordPacks loads every word pack in the directory of files.
+func LoadWordPacks(files fs.FS, directory string) ([]*WordPack, error) {
+	entries, err := fs.ReadDir(files, directory)
+	if err != nil {
+		return nil, err
+	}
+	packs := make([]*WordPack, 0)
+	for _, file := range entries {
+		if file.IsDir() {
+			continue
+		}
+		packPath := path.Join(directory, file.Name())
+		var pack *WordPack
+		switch strings.ToLower(path.Ext(packPath)) {
+		case ".txt":
+			pack, err = loadTextWordPack(files, packPath)
+		case ".json":
+			pack, err = loadJSONWordPack(files, packPath)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if pack.Name == "" {
+			pack.Name = strings.TrimSuffix(file.Name(), path.Ext(packPath))
+		}
+		err = pack.validate()
+		if err != nil {
+			return nil, err
+		}
+		pack.rankWords(FindKeyboardLayout(DefaultKeyboardLayout))
+		packs = append(packs, pack)
+	}
+	if len(packs) == 0 {
+		return nil, fmt.Errorf("%s: no word packs found", directory)
+	}
+	sort.SliceStable(packs, func(i, j int) bool {
+		return packs[i].Name < packs[j].Name
+	})
+	return packs, nil
+}
+
+func loadJSONWordPack(files fs.FS, path string) (*WordPack, error) {
+	data, err := fs.ReadFile(files, path)
+	if err != nil {
+		return nil, err
+	}
+	pack := &WordPack{}
+	err = json.Unmarshal(data, pack)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	pack.path = path
+	return pack, nil
+}
+
+// Text word packs contain one word per line. Lines with several words are
+// sentences for the boss waves. Lines starting with "#" are comments,
+// unless they are written as "# key: value" where key is one of name,
+// language, difficulty or characters.
+func loadTextWordPack(files fs.FS, path string) (*WordPack, error) {
+	file, err := files.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	pack := &WordPack{path: path}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			parts := strings.SplitN(strings.TrimPrefix(line, "#"), ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			value := strings.TrimSpace(parts[1])
+			switch strings.ToLower(strings.TrimSpace(parts[0])) {
+			case "name":
+				pack.Name = value
+			case "language":
+				pack.Language = value
+			case "difficulty":
+				pack.Difficulty = value
+			case "characters":
+				pack.Characters = value
+			}
+			continue
+		}
+		if strings.ContainsAny(line, " \t") {
+			pack.Sentences = append(pack.Sentences, strings.Join(strings.Fields(line), " "))
+			continue
+		}
+		pack.Words = append(pack.Words, line)
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return pack, nil
+}
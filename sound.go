@@ -0,0 +1,125 @@
+package main
+
+import (
+	"github.com/veandco/go-sdl2/mix"
+)
+
+var (
+	soundChannels        int = 16
+	soundTypingChannels  int = 4
+	soundTypingGroup     int = 1
+	soundEffectsGroup    int = 2
+	soundPanningMaxValue int = 255
+
+	soundKeystrokePath string = "resources/sounds/keystroke.wav"
+	soundMistypePath   string = "resources/sounds/mistype.wav"
+	soundLockPath      string = "resources/sounds/lock.wav"
+	soundExplosionPath string = "resources/sounds/explosion.wav"
+	soundImpactPath    string = "resources/sounds/impact.wav"
+	soundLevelUpPath   string = "resources/sounds/levelup.wav"
+	soundGameOverPath  string = "resources/sounds/gameover.wav"
+
+	soundKeystroke *Sound
+	soundMistype   *Sound
+	soundLock      *Sound
+	soundExplosion *Sound
+	soundImpact    *Sound
+	soundLevelUp   *Sound
+	soundGameOver  *Sound
+)
+
+// Sound is a sample played on one of the channels of its group. When all
+// channels of the group are busy the one that has played the longest is
+// cut off, so fast typing never drowns out an explosion.
+type Sound struct {
+	chunk *mix.Chunk
+	group int
+}
+
+func LoadSound(path string, group int) (*Sound, error) {
+	chunk, err := resources.Chunk(path)
+	if err != nil {
+		return nil, err
+	}
+	sound := &Sound{}
+	sound.chunk = chunk
+	sound.group = group
+	return sound, nil
+}
+
+func (sound *Sound) channel() int {
+	channel := mix.GroupAvailable(sound.group)
+	if channel == -1 {
+		channel = mix.GroupOldest(sound.group)
+	}
+	return channel
+}
+
+func (sound *Sound) Play() {
+	channel := sound.channel()
+	mix.SetPanning(channel, uint8(soundPanningMaxValue), uint8(soundPanningMaxValue))
+	sound.chunk.Play(channel, 0)
+}
+
+// PlayAt plays the sound panned to where x is on the screen, from the left
+// speaker at 0 to the right speaker at ScreenWidth.
+func (sound *Sound) PlayAt(x float32) {
+	position := x / float32(ScreenWidth)
+	if position < 0.0 {
+		position = 0.0
+	}
+	if position > 1.0 {
+		position = 1.0
+	}
+	left, right := float32(soundPanningMaxValue), float32(soundPanningMaxValue)
+	if position < 0.5 {
+		right *= position * 2.0
+	} else {
+		left *= (1.0 - position) * 2.0
+	}
+	channel := sound.channel()
+	mix.SetPanning(channel, uint8(left), uint8(right))
+	sound.chunk.Play(channel, 0)
+}
+
+func (sound *Sound) Free() {
+	sound.chunk.Free()
+}
+
+func loadSound(path string, group int) *Sound {
+	sound, err := LoadSound(path, group)
+	if err != nil {
+		exitWithError(err)
+	}
+	return sound
+}
+
+// The first channels are reserved for the music, the sound effects use the
+// ones after them.
+func initSounds() {
+	mix.AllocateChannels(musicChannels + soundChannels)
+	mix.ReserveChannels(musicChannels)
+	mix.GroupChannels(musicChannels, musicChannels+soundTypingChannels-1, soundTypingGroup)
+	mix.GroupChannels(musicChannels+soundTypingChannels, musicChannels+soundChannels-1, soundEffectsGroup)
+
+	soundKeystroke = loadSound(soundKeystrokePath, soundTypingGroup)
+	soundMistype = loadSound(soundMistypePath, soundTypingGroup)
+	soundLock = loadSound(soundLockPath, soundTypingGroup)
+	soundExplosion = loadSound(soundExplosionPath, soundEffectsGroup)
+	soundImpact = loadSound(soundImpactPath, soundEffectsGroup)
+	soundLevelUp = loadSound(soundLevelUpPath, soundEffectsGroup)
+	soundGameOver = loadSound(soundGameOverPath, soundEffectsGroup)
+}
+
+func setSoundVolume(volume int) {
+	for channel := musicChannels; channel < musicChannels+soundChannels; channel++ {
+		mix.Volume(channel, volume*mix.MAX_VOLUME/100)
+	}
+}
+
+func freeSounds() {
+	for _, sound := range []*Sound{soundKeystroke, soundMistype, soundLock,
+		soundExplosion, soundImpact, soundLevelUp, soundGameOver} {
+		sound.Free()
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/snosscire/astrotyper/stats"
+)
+
+var (
+	statisticsExportPath string
+
+	overlayStatistics []*Text
+)
+
+func exportStatistics(recorder *stats.Recorder) {
+	if statisticsExportPath == "" {
+		return
+	}
+	file, err := os.Create(statisticsExportPath)
+	if err == nil {
+		if strings.ToLower(filepath.Ext(statisticsExportPath)) == ".csv" {
+			err = recorder.WriteCSV(file)
+		} else {
+			err = recorder.WriteJSON(file)
+		}
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not export statistics: %v\n", err)
+	}
+}
+
+func keyStatList(keyStats []stats.KeyStat) string {
+	if len(keyStats) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(keyStats))
+	for _, stat := range keyStats {
+		keys = append(keys, stat.Key)
+	}
+	return strings.Join(keys, " ")
+}
+
+func fingerMissList(fingerStats []stats.KeyStat) string {
+	misses := make([]string, 0, len(fingerStats))
+	for _, stat := range fingerStats {
+		if stat.Misses > 0 {
+			misses = append(misses, fmt.Sprintf("%s %d", stat.Key, stat.Misses))
+		}
+	}
+	if len(misses) == 0 {
+		return "-"
+	}
+	return strings.Join(misses, ", ")
+}
+
+func updateStatisticsOverlay(summary stats.Summary) {
+	lines := []string{
+		fmt.Sprintf("%.1f WPM   %.1f%% accuracy   %d keystrokes",
+			summary.WordsPerMinute, summary.Accuracy*100.0, summary.Keystrokes),
+		fmt.Sprintf("Slowest keys: %s   Most missed keys: %s",
+			keyStatList(summary.SlowestKeys), keyStatList(summary.MostMissedKeys)),
+		fmt.Sprintf("Slowest pairs: %s   Most missed pairs: %s",
+			keyStatList(summary.SlowestBigrams), keyStatList(summary.MostMissedBigrams)),
+		fmt.Sprintf("Misses by finger: %s", fingerMissList(summary.Fingers)),
+	}
+	for len(overlayStatistics) < len(lines) {
+		overlayStatistics = append(overlayStatistics, NewText(fontPath, hudFontSize))
+	}
+	for i, line := range lines {
+		overlayStatistics[i].Update(line, applicationRenderer)
+	}
+}
+
+func drawStatisticsOverlay(y int32) int32 {
+	for _, text := range overlayStatistics {
+		text.Draw(applicationRenderer, (ScreenWidth/2)-(text.Width()/2), y)
+		y += text.Height()
+	}
+	return y
+}
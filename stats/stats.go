@@ -0,0 +1,305 @@
+// Package stats records every keystroke of a game and summarizes how fast
+// and how accurately the player typed.
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+var (
+	summaryTopCount    int     = 5
+	summaryMinSamples  int     = 3
+	charactersPerWord  float64 = 5.0
+	millisecondsPerMin float64 = 60000.0
+
+	// FingerNames names the fingers from the left little finger at 0 to
+	// the right little finger at 9.
+	FingerNames []string = []string{
+		"left little", "left ring", "left middle", "left index", "left thumb",
+		"right thumb", "right index", "right middle", "right ring", "right little",
+	}
+)
+
+type Keystroke struct {
+	Time     float32
+	Typed    rune
+	Expected rune
+	Previous rune
+	Finger   int
+	Correct  bool
+	Interval float32
+}
+
+type keystrokeJSON struct {
+	Time     float32 `json:"time"`
+	Typed    string  `json:"typed"`
+	Expected string  `json:"expected,omitempty"`
+	Previous string  `json:"previous,omitempty"`
+	Finger   string  `json:"finger,omitempty"`
+	Correct  bool    `json:"correct"`
+	Interval float32 `json:"interval"`
+}
+
+func runeString(character rune) string {
+	if character == 0 {
+		return ""
+	}
+	return string(character)
+}
+
+// FingerName returns the name of the finger, or "" for -1 when the key is
+// not on the keyboard layout.
+func FingerName(finger int) string {
+	if finger < 0 || finger >= len(FingerNames) {
+		return ""
+	}
+	return FingerNames[finger]
+}
+
+func (keystroke Keystroke) MarshalJSON() ([]byte, error) {
+	return json.Marshal(keystrokeJSON{
+		Time:     keystroke.Time,
+		Typed:    runeString(keystroke.Typed),
+		Expected: runeString(keystroke.Expected),
+		Previous: runeString(keystroke.Previous),
+		Finger:   FingerName(keystroke.Finger),
+		Correct:  keystroke.Correct,
+		Interval: keystroke.Interval,
+	})
+}
+
+type KeyStat struct {
+	Key             string  `json:"key"`
+	Count           int     `json:"count"`
+	Misses          int     `json:"misses"`
+	AverageInterval float32 `json:"averageInterval"`
+	totalInterval   float32
+	timedCount      int
+}
+
+type Summary struct {
+	Keystrokes        int       `json:"keystrokes"`
+	Correct           int       `json:"correct"`
+	WordsPerMinute    float64   `json:"wpm"`
+	Accuracy          float64   `json:"accuracy"`
+	SlowestKeys       []KeyStat `json:"slowestKeys"`
+	MostMissedKeys    []KeyStat `json:"mostMissedKeys"`
+	SlowestBigrams    []KeyStat `json:"slowestBigrams"`
+	MostMissedBigrams []KeyStat `json:"mostMissedBigrams"`
+	Fingers           []KeyStat `json:"fingers"`
+}
+
+type Recorder struct {
+	keystrokes []Keystroke
+	duration   float32
+}
+
+func NewRecorder() *Recorder {
+	recorder := &Recorder{}
+	recorder.Reset()
+	return recorder
+}
+
+func (recorder *Recorder) Reset() {
+	recorder.keystrokes = make([]Keystroke, 0)
+	recorder.duration = 0.0
+}
+
+// Record adds a keystroke made at the given game time. Expected is the
+// character the player should have typed, or 0 if no asteroid matched,
+// and previous is the character before it in the same word, or 0 at the
+// start of a word. Finger is the finger that types the expected character
+// on the player's keyboard layout, or -1 if it is not known.
+func (recorder *Recorder) Record(time float32, typed, expected, previous rune, finger int, correct bool) {
+	var interval float32
+	if len(recorder.keystrokes) > 0 {
+		interval = time - recorder.keystrokes[len(recorder.keystrokes)-1].Time
+	}
+	recorder.keystrokes = append(recorder.keystrokes, Keystroke{
+		Time:     time,
+		Typed:    typed,
+		Expected: expected,
+		Previous: previous,
+		Finger:   finger,
+		Correct:  correct,
+		Interval: interval,
+	})
+}
+
+func (recorder *Recorder) SetDuration(duration float32) {
+	recorder.duration = duration
+}
+
+func (recorder *Recorder) Keystrokes() []Keystroke {
+	return recorder.keystrokes
+}
+
+func (recorder *Recorder) Correct() int {
+	correct := 0
+	for _, keystroke := range recorder.keystrokes {
+		if keystroke.Correct {
+			correct++
+		}
+	}
+	return correct
+}
+
+func (recorder *Recorder) WordsPerMinute() float64 {
+	if recorder.duration <= 0.0 {
+		return 0.0
+	}
+	minutes := float64(recorder.duration) / millisecondsPerMin
+	return (float64(recorder.Correct()) / charactersPerWord) / minutes
+}
+
+func (recorder *Recorder) Accuracy() float64 {
+	if len(recorder.keystrokes) == 0 {
+		return 0.0
+	}
+	return float64(recorder.Correct()) / float64(len(recorder.keystrokes))
+}
+
+func addKeyStat(keyStats map[string]*KeyStat, key string, keystroke Keystroke) {
+	stat, ok := keyStats[key]
+	if !ok {
+		stat = &KeyStat{Key: key}
+		keyStats[key] = stat
+	}
+	stat.Count++
+	if !keystroke.Correct {
+		stat.Misses++
+	} else if keystroke.Previous != 0 {
+		stat.totalInterval += keystroke.Interval
+		stat.timedCount++
+	}
+}
+
+func averageIntervals(keyStats map[string]*KeyStat) {
+	for _, stat := range keyStats {
+		if stat.timedCount > 0 {
+			stat.AverageInterval = stat.totalInterval / float32(stat.timedCount)
+		}
+	}
+}
+
+func slowest(keyStats map[string]*KeyStat) []KeyStat {
+	result := make([]KeyStat, 0)
+	for _, stat := range keyStats {
+		if stat.timedCount >= summaryMinSamples {
+			result = append(result, *stat)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AverageInterval == result[j].AverageInterval {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].AverageInterval > result[j].AverageInterval
+	})
+	if len(result) > summaryTopCount {
+		result = result[:summaryTopCount]
+	}
+	return result
+}
+
+func mostMissed(keyStats map[string]*KeyStat) []KeyStat {
+	result := make([]KeyStat, 0)
+	for _, stat := range keyStats {
+		if stat.Misses > 0 {
+			result = append(result, *stat)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Misses == result[j].Misses {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].Misses > result[j].Misses
+	})
+	if len(result) > summaryTopCount {
+		result = result[:summaryTopCount]
+	}
+	return result
+}
+
+func (recorder *Recorder) Summary() Summary {
+	keys := make(map[string]*KeyStat)
+	bigrams := make(map[string]*KeyStat)
+	fingers := make(map[string]*KeyStat)
+	for _, keystroke := range recorder.keystrokes {
+		if keystroke.Expected == 0 {
+			continue
+		}
+		addKeyStat(keys, string(keystroke.Expected), keystroke)
+		if keystroke.Previous != 0 {
+			addKeyStat(bigrams, string(keystroke.Previous)+string(keystroke.Expected), keystroke)
+		}
+		if keystroke.Finger >= 0 {
+			addKeyStat(fingers, FingerName(keystroke.Finger), keystroke)
+		}
+	}
+	averageIntervals(keys)
+	averageIntervals(bigrams)
+	averageIntervals(fingers)
+	return Summary{
+		Keystrokes:        len(recorder.keystrokes),
+		Correct:           recorder.Correct(),
+		WordsPerMinute:    recorder.WordsPerMinute(),
+		Accuracy:          recorder.Accuracy(),
+		SlowestKeys:       slowest(keys),
+		MostMissedKeys:    mostMissed(keys),
+		SlowestBigrams:    slowest(bigrams),
+		MostMissedBigrams: mostMissed(bigrams),
+		Fingers:           byFinger(fingers),
+	}
+}
+
+// byFinger lists the fingers that typed anything from left to right.
+func byFinger(fingerStats map[string]*KeyStat) []KeyStat {
+	result := make([]KeyStat, 0)
+	for _, name := range FingerNames {
+		stat, ok := fingerStats[name]
+		if ok {
+			result = append(result, *stat)
+		}
+	}
+	return result
+}
+
+func (recorder *Recorder) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Summary    Summary     `json:"summary"`
+		Keystrokes []Keystroke `json:"keystrokes"`
+	}{
+		recorder.Summary(),
+		recorder.keystrokes,
+	})
+}
+
+func (recorder *Recorder) WriteCSV(writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+	err := csvWriter.Write([]string{"time", "typed", "expected", "previous", "finger", "correct", "interval"})
+	if err != nil {
+		return err
+	}
+	for _, keystroke := range recorder.keystrokes {
+		err = csvWriter.Write([]string{
+			strconv.FormatFloat(float64(keystroke.Time), 'f', 1, 32),
+			runeString(keystroke.Typed),
+			runeString(keystroke.Expected),
+			runeString(keystroke.Previous),
+			FingerName(keystroke.Finger),
+			strconv.FormatBool(keystroke.Correct),
+			strconv.FormatFloat(float64(keystroke.Interval), 'f', 1, 32),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,123 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// typeWord records the word as typed correctly, one keystroke every
+// interval milliseconds from the given time, and returns the time after it.
+func typeWord(recorder *Recorder, time, interval float32, word string, fingers []int) float32 {
+	previous := rune(0)
+	for i, character := range []rune(word) {
+		recorder.Record(time, character, character, previous, fingers[i], true)
+		previous = character
+		time += interval
+	}
+	return time
+}
+
+func TestSummary(t *testing.T) {
+	recorder := NewRecorder()
+	time := float32(0.0)
+	for i := 0; i < 4; i++ {
+		time = typeWord(recorder, time, 100.0, "the", []int{3, 6, 2})
+	}
+	recorder.Record(time, 'r', 'e', 'h', 2, false)
+	recorder.Record(time+50.0, 'x', 0, 0, -1, false)
+	recorder.SetDuration(60000.0)
+
+	summary := recorder.Summary()
+	if summary.Keystrokes != 14 || summary.Correct != 12 {
+		t.Fatalf("got %d keystrokes with %d correct, want 14 with 12", summary.Keystrokes, summary.Correct)
+	}
+	if summary.WordsPerMinute != 12.0/charactersPerWord {
+		t.Errorf("got %v words per minute, want %v", summary.WordsPerMinute, 12.0/charactersPerWord)
+	}
+	if summary.Accuracy != 12.0/14.0 {
+		t.Errorf("got accuracy %v, want %v", summary.Accuracy, 12.0/14.0)
+	}
+	if len(summary.MostMissedKeys) != 1 || summary.MostMissedKeys[0].Key != "e" || summary.MostMissedKeys[0].Misses != 1 {
+		t.Errorf("got most missed keys %+v, want only e", summary.MostMissedKeys)
+	}
+	if len(summary.MostMissedBigrams) != 1 || summary.MostMissedBigrams[0].Key != "he" {
+		t.Errorf("got most missed bigrams %+v, want only he", summary.MostMissedBigrams)
+	}
+	if len(summary.SlowestBigrams) != 2 {
+		t.Fatalf("got slowest bigrams %+v, want th and he", summary.SlowestBigrams)
+	}
+	for _, bigram := range summary.SlowestBigrams {
+		if bigram.AverageInterval != 100.0 {
+			t.Errorf("got average interval %v for %s, want 100", bigram.AverageInterval, bigram.Key)
+		}
+	}
+
+	fingers := []string{"left middle", "left index", "right index"}
+	if len(summary.Fingers) != len(fingers) {
+		t.Fatalf("got fingers %+v, want %v", summary.Fingers, fingers)
+	}
+	for i, name := range fingers {
+		if summary.Fingers[i].Key != name {
+			t.Errorf("got finger %s at %d, want %s", summary.Fingers[i].Key, i, name)
+		}
+	}
+	if summary.Fingers[0].Count != 5 || summary.Fingers[0].Misses != 1 {
+		t.Errorf("got %+v for the left middle finger, want 5 keystrokes and 1 miss", summary.Fingers[0])
+	}
+}
+
+func TestEmptyRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	if recorder.WordsPerMinute() != 0.0 || recorder.Accuracy() != 0.0 {
+		t.Errorf("got %v words per minute and accuracy %v, want 0", recorder.WordsPerMinute(), recorder.Accuracy())
+	}
+	summary := recorder.Summary()
+	if summary.Keystrokes != 0 || len(summary.Fingers) != 0 {
+		t.Errorf("got %+v, want an empty summary", summary)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	recorder := NewRecorder()
+	recorder.Record(0.0, 'a', 'a', 0, 0, true)
+	recorder.Record(150.0, 'b', 'я', 'a', -1, false)
+
+	var buffer bytes.Buffer
+	err := recorder.WriteCSV(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "time,typed,expected,previous,finger,correct,interval\n" +
+		"0.0,a,a,,left little,true,0.0\n" +
+		"150.0,b,я,a,,false,150.0\n"
+	if buffer.String() != want {
+		t.Errorf("got CSV\n%s\nwant\n%s", buffer.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	recorder := NewRecorder()
+	recorder.Record(0.0, 'a', 'a', 0, 0, true)
+	recorder.SetDuration(1000.0)
+
+	var buffer bytes.Buffer
+	err := recorder.WriteJSON(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		Summary    Summary                  `json:"summary"`
+		Keystrokes []map[string]interface{} `json:"keystrokes"`
+	}
+	err = json.Unmarshal(buffer.Bytes(), &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Summary.Keystrokes != 1 || len(result.Keystrokes) != 1 {
+		t.Fatalf("got %s, want one keystroke", buffer.String())
+	}
+	if result.Keystrokes[0]["typed"] != "a" || result.Keystrokes[0]["finger"] != "left little" {
+		t.Errorf("got keystroke %v, want a typed with the left little finger", result.Keystrokes[0])
+	}
+}
@@ -14,9 +14,9 @@ type Text struct {
 
 func NewText(fontPath string, fontSize int) *Text {
 	text := &Text{}
-	font, err := ttf.OpenFont(fontPath, fontSize)
+	font, err := resources.Font(fontPath, fontSize)
 	if err != nil {
-		panic(err)
+		exitWithError(err)
 	}
 	text.font = font
 	return text
@@ -34,7 +34,8 @@ func (text *Text) Update(content string, renderer *sdl.Renderer) {
 	if text.font == nil {
 		return
 	}
-	surface, err := text.font.RenderUTF8Blended(
+	surface, err := renderText(
+		text.font,
 		content,
 		sdl.Color{
 			R: 255,
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/snosscire/astrotyper/simulation"
+)
+
+var (
+	wordPackDirectory   string = "resources/words"
+	wordPackDefaultName string = "English"
+
+	wordPacks        []*simulation.WordPack
+	wordPackSelected int
+)
+
+func currentWordPack() *simulation.WordPack {
+	return wordPacks[wordPackSelected]
+}
+
+func selectDefaultWordPack() {
+	for i, pack := range wordPacks {
+		if pack.Name == wordPackDefaultName {
+			wordPackSelected = i
+			return
+		}
+	}
+	wordPackSelected = 0
+}